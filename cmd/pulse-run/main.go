@@ -0,0 +1,206 @@
+// Package main is the entry point for the pulse-run binary, which runs a command, such as a
+// cron job, and sends a heartbeat to its updown.io pulse check when it succeeds.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/pulse"
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// Exit codes of pulse-run itself, as used by the timeout and env commands
+const (
+	exitHeartbeat  = 1
+	exitUsage      = 2
+	exitTimeout    = 124
+	exitNotRunning = 126
+	exitNotFound   = 127
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] -- command [args...]\n\n", os.Args[0])
+	fmt.Fprintln(flag.CommandLine.Output(), "Runs command, forwards its exit code, and sends a heartbeat to a pulse check when it succeeds.")
+	fmt.Fprintln(flag.CommandLine.Output(), "A failure to send the heartbeat is logged, and exits with code 1 only with -fail-on-heartbeat-error.")
+	fmt.Fprintln(flag.CommandLine.Output(), "\nOptions:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	var (
+		pulseURL     string
+		token        string
+		apiKey       string
+		baseURL      string
+		maxRuntime   time.Duration
+		jitter       time.Duration
+		timeout      time.Duration
+		attempts     int
+		signalStart  bool
+		alwaysSignal bool
+		failOnError  bool
+	)
+
+	flag.Usage = usage
+	flag.StringVar(&pulseURL, "url", os.Getenv("UPDOWN_PULSE_URL"), "pulse URL to send the heartbeats to, defaults to the UPDOWN_PULSE_URL env variable")
+	flag.StringVar(&token, "token", "", "token of the pulse check, to read its URL from the API instead of setting -url")
+	flag.StringVar(&apiKey, "api-key", os.Getenv("UPDOWN_API_KEY"), "updown.io API key used with -token, defaults to the UPDOWN_API_KEY env variable")
+	flag.StringVar(&baseURL, "base-url", "https://updown.io/api/", "base URL of the updown.io API")
+	flag.DurationVar(&maxRuntime, "max-runtime", 0, "maximum runtime of the command, after which it is terminated and fails, 0 means unlimited")
+	flag.DurationVar(&jitter, "jitter", 0, "maximum random delay before running the command, to spread jobs scheduled at the same time")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "timeout of each attempt at sending a heartbeat")
+	flag.IntVar(&attempts, "attempts", 5, "maximum number of attempts at sending a heartbeat")
+	flag.BoolVar(&signalStart, "signal-start", false, "send a heartbeat when the command starts too, for commands running longer than the period of the pulse check")
+	flag.BoolVar(&alwaysSignal, "always", false, "send a heartbeat when the command fails too, to only monitor that it runs")
+	flag.BoolVar(&failOnError, "fail-on-heartbeat-error", false, "exit with code 1 when the command succeeds but its heartbeat cannot be sent")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(exitUsage)
+	}
+
+	u, err := resolveURL(pulseURL, token, apiKey, baseURL)
+	if err != nil {
+		log.Printf("[ERROR] %s", err)
+		os.Exit(exitUsage)
+	}
+	sender := &pulse.Sender{
+		URL:           u,
+		Timeout:       timeout,
+		MaxAttempts:   attempts,
+		SignalStart:   signalStart,
+		SignalFailure: alwaysSignal,
+	}
+
+	// The signals are forwarded to the command, so that it decides when to stop
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	if jitter > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(jitter))))
+		select {
+		case <-timer.C:
+		case sig := <-signals:
+			os.Exit(128 + int(sig.(syscall.Signal)))
+		}
+	}
+
+	code := 0
+	err = sender.Run(context.Background(), func(ctx context.Context) error {
+		code = run(ctx, flag.Args(), maxRuntime, signals)
+		if code != 0 {
+			return fmt.Errorf("command exited with code %d", code)
+		}
+		return nil
+	})
+	if err != nil && code == 0 {
+		log.Printf("[ERROR] %s", err)
+		if failOnError {
+			code = exitHeartbeat
+		}
+	}
+	os.Exit(code)
+}
+
+// resolveURL returns the pulse URL set with -url, or else the one read from the API for -token
+func resolveURL(pulseURL, token, apiKey, baseURL string) (*pulse.URL, error) {
+	if pulseURL != "" {
+		if token != "" {
+			return nil, errors.New("-url and -token are mutually exclusive")
+		}
+		return pulse.ParseURL(pulseURL)
+	}
+
+	if token == "" {
+		return nil, errors.New("a pulse URL is required, set -url, UPDOWN_PULSE_URL or -token")
+	}
+	if apiKey == "" {
+		return nil, errors.New("an API key is required with -token, set -api-key or UPDOWN_API_KEY")
+	}
+
+	client := updown.NewClient(apiKey, &http.Client{Timeout: 30 * time.Second})
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing -base-url: %w", err)
+	}
+	client.BaseURL = u
+	client.UserAgent += " pulse-run"
+	client.MaxRetries = 3
+
+	return pulse.ResolveURL(client, token)
+}
+
+// run runs a command with the standard streams of pulse-run, and returns its exit code. The
+// command is terminated once maxRuntime is exceeded, and killed if it does not exit 10s later.
+func run(ctx context.Context, args []string, maxRuntime time.Duration, signals <-chan os.Signal) int {
+	if maxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxRuntime)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 10 * time.Second
+
+	if err := cmd.Start(); err != nil {
+		log.Printf("[ERROR] starting %s: %s", args[0], err)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return exitNotFound
+		}
+		return exitNotRunning
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("[ERROR] %s exceeded the maximum runtime of %s", args[0], maxRuntime)
+		return exitTimeout
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return exitErr.ExitCode()
+	default:
+		log.Printf("[ERROR] running %s: %s", args[0], err)
+		return exitNotRunning
+	}
+}
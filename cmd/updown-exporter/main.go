@@ -0,0 +1,89 @@
+// Package main is the entry point for the updown-exporter binary, which exposes the status
+// and the metrics of updown.io checks to Prometheus.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/exporter"
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+func main() {
+	var (
+		listen          string
+		apiKey          string
+		baseURL         string
+		refreshInterval time.Duration
+		metricsPeriod   time.Duration
+		rateLimit       float64
+		workers         int
+	)
+
+	flag.StringVar(&listen, "listen", ":9595", "address to serve /metrics on")
+	flag.StringVar(&apiKey, "api-key", os.Getenv("UPDOWN_API_KEY"), "updown.io API key, defaults to the UPDOWN_API_KEY env variable")
+	flag.StringVar(&baseURL, "base-url", "https://updown.io/api/", "base URL of the updown.io API")
+	flag.DurationVar(&refreshInterval, "refresh-interval", time.Minute, "interval between two reads of the checks and their metrics, scrapes are served from the last one")
+	flag.DurationVar(&metricsPeriod, "metrics-period", time.Hour, "period over which the metrics of the checks are aggregated")
+	flag.Float64Var(&rateLimit, "rate-limit", 1, "maximum number of requests per second sent to the API, 0 means unlimited")
+	flag.IntVar(&workers, "workers", 4, "number of checks whose metrics are read concurrently")
+	flag.Parse()
+
+	if apiKey == "" {
+		log.Fatal("an API key is required, set -api-key or UPDOWN_API_KEY")
+	}
+
+	client := updown.NewClient(apiKey, &http.Client{Timeout: 30 * time.Second})
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		log.Fatalf("parsing -base-url: %s", err)
+	}
+	client.BaseURL = u
+	client.UserAgent += " updown-exporter"
+	client.MaxRetries = 3
+	client.RateLimiter = updown.NewRateLimiter(rateLimit)
+
+	e := exporter.New(client, exporter.Options{
+		RefreshInterval: refreshInterval,
+		MetricsPeriod:   metricsPeriod,
+		Workers:         workers,
+	})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go e.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving metrics on %s/metrics", listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err.Error())
+	}
+}
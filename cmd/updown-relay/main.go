@@ -0,0 +1,68 @@
+// Package main is the entry point for the updown-relay binary, which forwards the events of
+// updown.io webhooks to Slack, Microsoft Teams or any HTTP endpoint.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/relay"
+)
+
+func main() {
+	var (
+		listen     string
+		configFile string
+		path       string
+		secret     string
+	)
+
+	flag.StringVar(&listen, "listen", ":9596", "address to receive the webhooks on")
+	flag.StringVar(&configFile, "config", "updown-relay.json", "path of the JSON configuration of the targets and routes")
+	flag.StringVar(&path, "path", "/updown", "path of the URL to set on the updown.io webhook recipient")
+	flag.StringVar(&secret, "secret", os.Getenv("UPDOWN_RELAY_SECRET"), "secret the webhooks must give in the token query parameter, defaults to the UPDOWN_RELAY_SECRET env variable")
+	flag.Parse()
+
+	cfg, err := relay.LoadConfig(configFile)
+	if err != nil {
+		log.Fatalf("reading configuration: %s", err)
+	}
+
+	r, err := relay.New(cfg)
+	if err != nil {
+		log.Fatalf("invalid configuration: %s", err)
+	}
+	r.Secret = secret
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle(path, r.Handler())
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("receiving webhooks on %s%s", listen, path)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err.Error())
+	}
+	<-done
+}
@@ -0,0 +1,64 @@
+// Package main is the entry point for the updown-tfgen binary, which generates the Terraform
+// configuration and the import blocks of the resources of an existing updown.io account.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/tfgen"
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+func main() {
+	var (
+		apiKey      string
+		baseURL     string
+		out         string
+		aliasPrefix string
+	)
+
+	flag.StringVar(&apiKey, "api-key", os.Getenv("UPDOWN_API_KEY"), "updown.io API key, defaults to the UPDOWN_API_KEY env variable")
+	flag.StringVar(&baseURL, "base-url", "https://updown.io/api/", "base URL of the updown.io API")
+	flag.StringVar(&out, "out", "", "file to write the configuration to, defaults to the standard output")
+	flag.StringVar(&aliasPrefix, "alias-prefix", "", "alias_prefix of the provider, stripped from the aliases of the checks and pulses")
+	flag.Parse()
+
+	if apiKey == "" {
+		log.Fatal("an API key is required, set -api-key or UPDOWN_API_KEY")
+	}
+
+	client := updown.NewClient(apiKey, &http.Client{Timeout: 30 * time.Second})
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		log.Fatalf("parsing -base-url: %s", err)
+	}
+	client.BaseURL = u
+	client.UserAgent += " updown-tfgen"
+	client.MaxRetries = 3
+
+	account, err := tfgen.Read(client)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	config := tfgen.Generate(account, tfgen.Options{AliasPrefix: aliasPrefix})
+
+	if out == "" {
+		_, err = os.Stdout.Write(config)
+	} else {
+		err = os.WriteFile(out, config, 0o644)
+	}
+	if err != nil {
+		log.Fatalf("writing the configuration: %s", err)
+	}
+	log.Printf("generated %d checks, %d pulses, %d recipients and %d status pages",
+		len(account.Checks), len(account.Pulses), len(account.Recipients), len(account.StatusPages))
+}
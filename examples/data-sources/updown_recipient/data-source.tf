@@ -0,0 +1,10 @@
+# Look up a slack channel set up from the web UI and alert it
+data "updown_recipient" "alerts" {
+  type = "slack"
+  name = "#alerts"
+}
+
+resource "updown_check" "mywebsite" {
+  url        = "https://example.com"
+  recipients = [data.updown_recipient.alerts.id]
+}
@@ -0,0 +1,8 @@
+# List the slack integrations set up from the web UI
+data "updown_recipients" "slack" {
+  type = "slack"
+}
+
+output "updown_slack_recipients" {
+  value = data.updown_recipients.slack.recipients[*].id
+}
@@ -0,0 +1,14 @@
+provider "updown" {
+  alias_prefix = "[tf] "
+}
+
+# List the checks which are not managed with Terraform
+data "updown_unmanaged_checks" "all" {}
+
+# Generate import blocks for them
+output "updown_unmanaged_imports" {
+  value = join("\n", [
+    for c in data.updown_unmanaged_checks.all.checks :
+    "import {\n  to = ${c.type == "pulse" ? "updown_pulse" : "updown_check"}.${c.token}\n  id = \"${c.token}\"\n}"
+  ])
+}
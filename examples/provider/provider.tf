@@ -1,4 +1,10 @@
 provider "updown" {
   # Can also be set using UPDOWN_API_KEY env variable.
   api_key = "<YOUR_UPDOWN_API_KEY>"
+
+  # Optional values applied to every check and pulse which does not set them
+  defaults {
+    recipients         = ["email:123456789"]
+    disabled_locations = ["mia"]
+  }
 }
@@ -2,3 +2,8 @@
 # It looks like the following regexp : ^https:\/\/updown.io\/([a-z0-9]{4})$
 
 terraform import updown_check.my_website <check_id>
+
+# Checks can also be imported by alias, with or without the provider alias_prefix,
+# or URL, which must match a single check
+terraform import updown_check.my_website "alias:<alias>"
+terraform import updown_check.my_website "url:<url>"
@@ -20,3 +20,18 @@ resource "updown_check" "mywebsite" {
     "email:123456789"
   ]
 }
+
+# Fail the deployment when the new endpoint is not healthy
+resource "updown_check" "api" {
+  url                   = "https://api.example.com/healthz"
+  wait_for_first_result = true
+  fail_if_down          = true
+
+  timeouts {
+    create = "10m"
+  }
+}
+
+output "api_ssl_valid" {
+  value = updown_check.api.ssl_valid
+}
@@ -0,0 +1,5 @@
+# The ID is made of the token of the check and the ID of the recipient
+terraform import updown_check_recipient.team <check_id>/slack_compatible:123456789
+
+# The check can also be designated by its alias or URL
+terraform import updown_check_recipient.team alias:API/slack_compatible:123456789
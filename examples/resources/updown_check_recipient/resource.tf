@@ -0,0 +1,16 @@
+# Owned by the platform team
+resource "updown_check" "api" {
+  url                        = "https://api.example.com/healthz"
+  ignore_external_recipients = true
+}
+
+# Owned by a product team, in another stack
+resource "updown_recipient" "team" {
+  type  = "slack_compatible"
+  value = "https://hooks.slack.com/services/T000/B000/XXXX"
+}
+
+resource "updown_check_recipient" "team" {
+  check_token  = updown_check.api.id
+  recipient_id = updown_recipient.team.id
+}
@@ -0,0 +1,22 @@
+resource "updown_check" "billing_api" {
+  url   = "https://billing.example.com/api/healthz"
+  alias = "billing-api"
+
+  # Leave the mute state to the maintenance windows
+  lifecycle {
+    ignore_changes = [mute_until]
+  }
+}
+
+# Mute the billing checks during the database migration
+resource "updown_maintenance_window" "db_migration" {
+  aliases = ["billing-*"]
+  start   = "2030-01-02T22:00:00Z"
+  end     = "2030-01-03T01:00:00Z"
+}
+
+# Mute a check for two hours from now
+resource "updown_maintenance_window" "hotfix" {
+  checks   = [updown_check.billing_api.id]
+  duration = "2h"
+}
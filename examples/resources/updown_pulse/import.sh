@@ -2,3 +2,12 @@
 # It looks like the following regexp : ^https:\/\/updown.io\/([a-z0-9]{4})$
 
 terraform import updown_pulse.my_job <check_id>
+
+# Checks can also be imported by alias, with or without the provider alias_prefix,
+# or URL, which must match a single check
+terraform import updown_pulse.my_job "alias:<alias>"
+terraform import updown_pulse.my_job "url:<url>"
+
+# The API redacts the secret part of the pulse URL, append the known URL to the ID
+# so that pulse_url can be set without modifying the check
+terraform import updown_pulse.my_job "<check_id>,https://pulse.updown.io/<check_id>/<secret>"
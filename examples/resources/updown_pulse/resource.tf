@@ -20,3 +20,11 @@ resource "updown_pulse" "frequent_job" {
   alias  = "data-sync"
   period = 300 # expect heartbeat every 5 minutes
 }
+
+# Keep the history of the check when the resource is destroyed
+resource "updown_pulse" "legacy_job" {
+  alias                 = "legacy-export"
+  period                = 3600
+  on_destroy            = "disable"
+  disabled_alias_suffix = " (decommissioned)"
+}
@@ -3,3 +3,7 @@
 # [{"id":"email:123456789","type":"email","name":"foo@bar.baz","immutable":false}]
 
 terraform import updown_recipient.my_recipient email:123456789
+
+# Recipients can also be imported by type and value, which must match a single recipient
+terraform import updown_recipient.my_recipient email:ops@example.com
+terraform import updown_recipient.my_recipient webhook:https://example.com/hook
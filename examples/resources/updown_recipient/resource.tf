@@ -2,3 +2,10 @@ resource "updown_recipient" "myrecipient" {
   type = "email"
   value = "foo@bar.baz"
 }
+
+resource "updown_recipient" "mywebhook" {
+  type     = "webhook"
+  name     = "Incident bot"
+  value    = "https://example.com/updown/hook"
+  selected = true
+}
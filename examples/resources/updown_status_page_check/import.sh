@@ -0,0 +1,2 @@
+# The ID is made of the token of the status page and the token of the check
+terraform import updown_status_page_check.billing <status_page_token>/<check_id>
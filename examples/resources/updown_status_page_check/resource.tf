@@ -0,0 +1,16 @@
+# Owned by the platform team
+resource "updown_status_page" "company" {
+  name                   = "Example Inc."
+  ignore_external_checks = true
+}
+
+# Owned by a service team, in another stack
+resource "updown_check" "billing" {
+  url = "https://billing.example.com/healthz"
+}
+
+resource "updown_status_page_check" "billing" {
+  status_page_token = updown_status_page.company.id
+  check_token       = updown_check.billing.id
+  position          = 1
+}
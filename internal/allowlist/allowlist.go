@@ -0,0 +1,186 @@
+// Package allowlist renders firewall allowlists from the updown.io testing nodes.
+package allowlist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// NodeLister lists the updown.io testing nodes, it is implemented by *updown.NodeService
+type NodeLister interface {
+	List() (updown.Nodes, *http.Response, error)
+	ListIPv4() (updown.IPs, *http.Response, error)
+	ListIPv6() (updown.IPs, *http.Response, error)
+}
+
+// Entry is a single network allowed by the allowlist
+type Entry struct {
+	// Prefix is the network to allow, a single address is represented as a /32 or /128
+	Prefix netip.Prefix
+	// Comment describes the entry (node name and location), it can be empty
+	Comment string
+}
+
+// Allowlist is a sorted and deduplicated set of networks
+type Allowlist struct {
+	Entries []Entry
+}
+
+// Fetch builds an Allowlist from the IPv4 and IPv6 addresses of the updown.io nodes. The node
+// details are only used to annotate the entries, the IP lists remain the source of truth.
+func Fetch(nodes NodeLister) (*Allowlist, error) {
+	details, _, err := nodes.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	ipv4, _, err := nodes.ListIPv4()
+	if err != nil {
+		return nil, fmt.Errorf("listing ipv4 addresses: %w", err)
+	}
+
+	ipv6, _, err := nodes.ListIPv6()
+	if err != nil {
+		return nil, fmt.Errorf("listing ipv6 addresses: %w", err)
+	}
+
+	// Nodes are visited in the order of their names, so that the comments of the addresses
+	// shared by several nodes are the same from one run to the next
+	names := make([]string, 0, len(details))
+	for name := range details {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	comments := map[netip.Addr]string{}
+	for _, name := range names {
+		node := details[name]
+		comment := nodeComment(name, node)
+		for _, raw := range []string{node.IP, node.IP6} {
+			addr, err := netip.ParseAddr(raw)
+			if err != nil {
+				continue
+			}
+			addr = addr.Unmap()
+			if comments[addr] != "" {
+				comments[addr] += "; " + comment
+			} else {
+				comments[addr] = comment
+			}
+		}
+	}
+
+	var entries []Entry
+	for _, raw := range append(append([]string{}, ipv4...), ipv6...) {
+		prefix, err := parsePrefix(raw)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Prefix: prefix, Comment: comments[prefix.Addr()]})
+	}
+
+	return New(entries), nil
+}
+
+// New returns an Allowlist containing the given entries, sorted and deduplicated. When the same
+// network appears more than once, the first non-empty comment is kept.
+func New(entries []Entry) *Allowlist {
+	byPrefix := map[netip.Prefix]int{}
+	l := &Allowlist{}
+	for _, e := range entries {
+		e.Prefix = e.Prefix.Masked()
+		if i, ok := byPrefix[e.Prefix]; ok {
+			if l.Entries[i].Comment == "" {
+				l.Entries[i].Comment = e.Comment
+			}
+			continue
+		}
+		byPrefix[e.Prefix] = len(l.Entries)
+		l.Entries = append(l.Entries, e)
+	}
+
+	sort.Slice(l.Entries, func(i, j int) bool {
+		a, b := l.Entries[i].Prefix, l.Entries[j].Prefix
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+
+	return l
+}
+
+// IPv4 returns the IPv4 entries of the allowlist
+func (l *Allowlist) IPv4() []Entry {
+	return l.filter(func(e Entry) bool { return e.Prefix.Addr().Is4() })
+}
+
+// IPv6 returns the IPv6 entries of the allowlist
+func (l *Allowlist) IPv6() []Entry {
+	return l.filter(func(e Entry) bool { return e.Prefix.Addr().Is6() })
+}
+
+// Digest returns a hex encoded SHA-256 of the networks of the allowlist. It ignores comments and
+// only changes when the set of networks changes.
+func (l *Allowlist) Digest() string {
+	var b strings.Builder
+	for _, e := range l.Entries {
+		b.WriteString(e.Prefix.String())
+		b.WriteByte('\n')
+	}
+	return Checksum([]byte(b.String()))
+}
+
+// Checksum returns the hex encoded SHA-256 of a rendered allowlist, it can be compared to the
+// checksum of the previously deployed file in order to only reload on real changes.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *Allowlist) filter(keep func(Entry) bool) []Entry {
+	var res []Entry
+	for _, e := range l.Entries {
+		if keep(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// parsePrefix parses either a CIDR or a single address into a prefix
+func parsePrefix(raw string) (netip.Prefix, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.Contains(raw, "/") {
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("parsing network %q: %w", raw, err)
+		}
+		return prefix, nil
+	}
+
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("parsing address %q: %w", raw, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func nodeComment(name string, node updown.NodeDetails) string {
+	parts := []string{name}
+	if node.City != "" {
+		parts = append(parts, node.City)
+	}
+	if node.CountryCode != "" {
+		parts = append(parts, node.CountryCode)
+	}
+	return strings.Join(parts, ", ")
+}
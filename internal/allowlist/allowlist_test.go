@@ -0,0 +1,154 @@
+package allowlist
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup creates a test HTTP server serving the nodes endpoints and a Client configured to talk
+// to it, and a teardown function that must be called when the test is done.
+func setup() (mux *http.ServeMux, client *updown.Client, teardown func()) {
+	mux = http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client = updown.NewClient("test-api-key", nil)
+	u, _ := url.Parse(server.URL + "/")
+	client.BaseURL = u
+
+	return mux, client, server.Close
+}
+
+func handleNodes(mux *http.ServeMux) {
+	for path, body := range map[string]string{
+		"/nodes": `{
+			"lan": {"ip": "45.32.74.41", "ip6": "2001:19f0:6001:2c6::1", "city": "Los Angeles", "country_code": "US"},
+			"fra": {"ip": "104.238.159.87", "ip6": "2001:19f0:6c01:145::1", "city": "Frankfurt", "country_code": "DE"}
+		}`,
+		"/nodes/ipv4": `["104.238.159.87", "45.32.74.41", "104.238.159.87"]`,
+		"/nodes/ipv6": `["2001:19f0:6c01:145::1", "2001:19f0:6001:2c6::1"]`,
+	} {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, body)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	handleNodes(mux)
+
+	l, err := Fetch(&client.Node)
+	require.NoError(t, err)
+	require.Len(t, l.Entries, 4)
+
+	assert.Equal(t, "45.32.74.41/32", l.Entries[0].Prefix.String())
+	assert.Equal(t, "lan, Los Angeles, US", l.Entries[0].Comment)
+	assert.Equal(t, "104.238.159.87/32", l.Entries[1].Prefix.String())
+	assert.Equal(t, "2001:19f0:6001:2c6::1/128", l.Entries[2].Prefix.String())
+	assert.Equal(t, "2001:19f0:6c01:145::1/128", l.Entries[3].Prefix.String())
+	assert.Equal(t, "fra, Frankfurt, DE", l.Entries[3].Comment)
+
+	assert.Len(t, l.IPv4(), 2)
+	assert.Len(t, l.IPv6(), 2)
+}
+
+func TestFetch_SharedAddresses(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	for path, body := range map[string]string{
+		"/nodes": `{
+			"sgp": {"ip": "45.32.107.181", "city": "Singapore", "country_code": "SG"},
+			"lan": {"ip": "45.32.107.181", "city": "Los Angeles", "country_code": "US"},
+			"fra": {"ip": "45.32.107.181", "city": "Frankfurt", "country_code": "DE"}
+		}`,
+		"/nodes/ipv4": `["45.32.107.181"]`,
+		"/nodes/ipv6": `[]`,
+	} {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+
+	// Comments are built in the order of the node names, whatever the order of the map
+	for i := 0; i < 10; i++ {
+		l, err := Fetch(&client.Node)
+		require.NoError(t, err)
+		require.Len(t, l.Entries, 1)
+		assert.Equal(t, "fra, Frankfurt, DE; lan, Los Angeles, US; sgp, Singapore, SG", l.Entries[0].Comment)
+	}
+}
+
+func TestFetch_Error(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/nodes", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := Fetch(&client.Node)
+	assert.Error(t, err)
+}
+
+func TestNew_SortsAndDeduplicates(t *testing.T) {
+	l := New([]Entry{
+		{Prefix: netip.MustParsePrefix("2001:db8::1/128")},
+		{Prefix: netip.MustParsePrefix("10.0.0.0/8")},
+		{Prefix: netip.MustParsePrefix("10.1.2.3/8"), Comment: "private"},
+		{Prefix: netip.MustParsePrefix("1.2.3.4/32")},
+	})
+
+	require.Len(t, l.Entries, 3)
+	assert.Equal(t, "1.2.3.4/32", l.Entries[0].Prefix.String())
+	assert.Equal(t, "10.0.0.0/8", l.Entries[1].Prefix.String())
+	assert.Equal(t, "private", l.Entries[1].Comment)
+	assert.Equal(t, "2001:db8::1/128", l.Entries[2].Prefix.String())
+}
+
+func TestDigest_IgnoresOrderAndComments(t *testing.T) {
+	a := New([]Entry{
+		{Prefix: netip.MustParsePrefix("1.2.3.4/32"), Comment: "a"},
+		{Prefix: netip.MustParsePrefix("5.6.7.8/32")},
+	})
+	b := New([]Entry{
+		{Prefix: netip.MustParsePrefix("5.6.7.8/32"), Comment: "b"},
+		{Prefix: netip.MustParsePrefix("1.2.3.4/32")},
+	})
+	c := New([]Entry{
+		{Prefix: netip.MustParsePrefix("1.2.3.4/32")},
+	})
+
+	assert.Equal(t, a.Digest(), b.Digest())
+	assert.NotEqual(t, a.Digest(), c.Digest())
+	assert.Len(t, a.Digest(), 64)
+}
+
+func TestParsePrefix(t *testing.T) {
+	p, err := parsePrefix("1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4/32", p.String())
+
+	p, err = parsePrefix("::ffff:1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4/32", p.String())
+
+	p, err = parsePrefix("2001:db8::/32")
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::/32", p.String())
+
+	_, err = parsePrefix("not-an-ip")
+	assert.Error(t, err)
+}
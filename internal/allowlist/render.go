@@ -0,0 +1,226 @@
+// Package allowlist renders firewall allowlists from the updown.io testing nodes.
+package allowlist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Format represents an output format for an allowlist
+type Format string
+
+// Output formats
+const (
+	FormatCIDR     Format = "cidr"
+	FormatNginx    Format = "nginx"
+	FormatNftables Format = "nftables"
+	FormatIptables Format = "iptables"
+	FormatApache   Format = "apache"
+	FormatHAProxy  Format = "haproxy"
+)
+
+// Formats lists all the supported output formats
+var Formats = []Format{FormatCIDR, FormatNginx, FormatNftables, FormatIptables, FormatApache, FormatHAProxy}
+
+// Family restricts the rendered entries to an address family
+type Family string
+
+// Address families
+const (
+	FamilyAll  Family = ""
+	FamilyIPv4 Family = "ipv4"
+	FamilyIPv6 Family = "ipv6"
+)
+
+const (
+	defaultNftablesTable = "filter"
+	defaultSetName       = "updown"
+	defaultChain         = "UPDOWN"
+	defaultTarget        = "ACCEPT"
+)
+
+// ErrUnsupportedFormat indicates that the requested output format is not known
+var ErrUnsupportedFormat = errors.New("unsupported allowlist format")
+
+// ErrFamilyRequired indicates that the output format can only hold a single address family
+var ErrFamilyRequired = errors.New("format requires a single address family (ipv4 or ipv6)")
+
+// Options tunes the rendering of an allowlist
+type Options struct {
+	// Only render the entries of this address family
+	Family Family
+	// Do not render comments
+	NoComments bool
+	// nftables table holding the sets (default: filter)
+	NftablesTable string
+	// Base name of the nftables sets, suffixed with _ipv4 and _ipv6 (default: updown)
+	SetName string
+	// iptables chain the rules are appended to (default: UPDOWN)
+	Chain string
+	// iptables target of the rules (default: ACCEPT)
+	Target string
+}
+
+// Render renders the allowlist in the given format. The output is deterministic: rendering the
+// same networks twice produces byte for byte identical content.
+func (l *Allowlist) Render(format Format, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := l.Write(&buf, format, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write renders the allowlist in the given format to w
+func (l *Allowlist) Write(w io.Writer, format Format, opts Options) error {
+	opts.setDefaults()
+
+	var entries []Entry
+	switch opts.Family {
+	case FamilyIPv4:
+		entries = l.IPv4()
+	case FamilyIPv6:
+		entries = l.IPv6()
+	case FamilyAll:
+		entries = l.Entries
+	default:
+		return fmt.Errorf("unsupported address family %q", opts.Family)
+	}
+
+	r := &renderer{w: w, opts: opts}
+	switch format {
+	case FormatCIDR:
+		// Plain lists are consumed by tools which do not all support comments
+		r.opts.NoComments = true
+		r.lines(entries, "%s")
+	case FormatNginx:
+		r.header()
+		r.lines(entries, "allow %s;")
+	case FormatApache:
+		r.header()
+		r.linesCommentAbove(entries, "Require ip %s")
+	case FormatHAProxy:
+		r.header()
+		r.linesCommentAbove(entries, "%s")
+	case FormatNftables:
+		r.header()
+		r.nftables(l, opts.Family)
+	case FormatIptables:
+		if opts.Family == FamilyAll {
+			return ErrFamilyRequired
+		}
+		r.header()
+		r.iptables(entries)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	return r.err
+}
+
+func (o *Options) setDefaults() {
+	if o.NftablesTable == "" {
+		o.NftablesTable = defaultNftablesTable
+	}
+	if o.SetName == "" {
+		o.SetName = defaultSetName
+	}
+	if o.Chain == "" {
+		o.Chain = defaultChain
+	}
+	if o.Target == "" {
+		o.Target = defaultTarget
+	}
+}
+
+// renderer writes lines and remembers the first write error
+type renderer struct {
+	w    io.Writer
+	opts Options
+	err  error
+}
+
+func (r *renderer) printf(format string, args ...interface{}) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = fmt.Fprintf(r.w, format, args...)
+}
+
+func (r *renderer) header() {
+	if !r.opts.NoComments {
+		r.printf("# updown.io testing nodes, generated file, do not edit\n")
+	}
+}
+
+func (r *renderer) comment(e Entry) string {
+	if r.opts.NoComments || e.Comment == "" {
+		return ""
+	}
+	return " # " + e.Comment
+}
+
+func (r *renderer) lines(entries []Entry, format string) {
+	for _, e := range entries {
+		r.printf(format+"%s\n", e.Prefix, r.comment(e))
+	}
+}
+
+// linesCommentAbove is used by formats which do not support trailing comments
+func (r *renderer) linesCommentAbove(entries []Entry, format string) {
+	for _, e := range entries {
+		if !r.opts.NoComments && e.Comment != "" {
+			r.printf("# %s\n", e.Comment)
+		}
+		r.printf(format+"\n", e.Prefix)
+	}
+}
+
+func (r *renderer) nftables(l *Allowlist, family Family) {
+	r.printf("table inet %s {\n", r.opts.NftablesTable)
+	first := true
+	for _, set := range []struct {
+		family  Family
+		addr    string
+		entries []Entry
+	}{
+		{FamilyIPv4, "ipv4_addr", l.IPv4()},
+		{FamilyIPv6, "ipv6_addr", l.IPv6()},
+	} {
+		if family != FamilyAll && family != set.family {
+			continue
+		}
+		if !first {
+			r.printf("\n")
+		}
+		first = false
+
+		r.printf("\tset %s_%s {\n", r.opts.SetName, set.family)
+		r.printf("\t\ttype %s\n", set.addr)
+		r.printf("\t\tflags interval\n")
+		if len(set.entries) > 0 {
+			r.printf("\t\telements = {\n")
+			for i, e := range set.entries {
+				sep := ","
+				if i == len(set.entries)-1 {
+					sep = ""
+				}
+				r.printf("\t\t\t%s%s%s\n", e.Prefix, sep, r.comment(e))
+			}
+			r.printf("\t\t}\n")
+		}
+		r.printf("\t}\n")
+	}
+	r.printf("}\n")
+}
+
+func (r *renderer) iptables(entries []Entry) {
+	r.printf("*filter\n")
+	r.printf(":%s - [0:0]\n", r.opts.Chain)
+	for _, e := range entries {
+		r.printf("-A %s -s %s -j %s\n", r.opts.Chain, e.Prefix, r.opts.Target)
+	}
+	r.printf("COMMIT\n")
+}
@@ -0,0 +1,106 @@
+package allowlist
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAllowlist() *Allowlist {
+	return New([]Entry{
+		{Prefix: netip.MustParsePrefix("2001:db8::1/128"), Comment: "fra"},
+		{Prefix: netip.MustParsePrefix("5.6.7.8/32"), Comment: "fra"},
+		{Prefix: netip.MustParsePrefix("1.2.3.4/32"), Comment: "lan"},
+	})
+}
+
+func TestRender_CIDR(t *testing.T) {
+	out, err := testAllowlist().Render(FormatCIDR, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4/32\n5.6.7.8/32\n2001:db8::1/128\n", string(out))
+}
+
+func TestRender_Nginx(t *testing.T) {
+	out, err := testAllowlist().Render(FormatNginx, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, `# updown.io testing nodes, generated file, do not edit
+allow 1.2.3.4/32; # lan
+allow 5.6.7.8/32; # fra
+allow 2001:db8::1/128; # fra
+`, string(out))
+}
+
+func TestRender_Apache(t *testing.T) {
+	out, err := testAllowlist().Render(FormatApache, Options{Family: FamilyIPv4})
+	require.NoError(t, err)
+	assert.Equal(t, `# updown.io testing nodes, generated file, do not edit
+# lan
+Require ip 1.2.3.4/32
+# fra
+Require ip 5.6.7.8/32
+`, string(out))
+}
+
+func TestRender_HAProxy(t *testing.T) {
+	out, err := testAllowlist().Render(FormatHAProxy, Options{NoComments: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3.4/32\n5.6.7.8/32\n2001:db8::1/128\n", string(out))
+}
+
+func TestRender_Nftables(t *testing.T) {
+	out, err := testAllowlist().Render(FormatNftables, Options{NoComments: true, NftablesTable: "fw"})
+	require.NoError(t, err)
+	assert.Equal(t, `table inet fw {
+	set updown_ipv4 {
+		type ipv4_addr
+		flags interval
+		elements = {
+			1.2.3.4/32,
+			5.6.7.8/32
+		}
+	}
+
+	set updown_ipv6 {
+		type ipv6_addr
+		flags interval
+		elements = {
+			2001:db8::1/128
+		}
+	}
+}
+`, string(out))
+}
+
+func TestRender_Iptables(t *testing.T) {
+	out, err := testAllowlist().Render(FormatIptables, Options{Family: FamilyIPv6, Chain: "HEALTH"})
+	require.NoError(t, err)
+	assert.Equal(t, `# updown.io testing nodes, generated file, do not edit
+*filter
+:HEALTH - [0:0]
+-A HEALTH -s 2001:db8::1/128 -j ACCEPT
+COMMIT
+`, string(out))
+}
+
+func TestRender_IptablesRequiresFamily(t *testing.T) {
+	_, err := testAllowlist().Render(FormatIptables, Options{})
+	assert.Equal(t, ErrFamilyRequired, err)
+}
+
+func TestRender_UnsupportedFormat(t *testing.T) {
+	_, err := testAllowlist().Render(Format("pf"), Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	for _, format := range Formats {
+		opts := Options{Family: FamilyIPv4}
+		a, err := testAllowlist().Render(format, opts)
+		require.NoError(t, err)
+		b, err := New(append([]Entry{}, testAllowlist().Entries...)).Render(format, opts)
+		require.NoError(t, err)
+		assert.Equal(t, Checksum(a), Checksum(b), "format %s", format)
+	}
+}
@@ -0,0 +1,203 @@
+// Package exporter exposes the status and the metrics of updown.io checks to Prometheus.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "updown"
+
+// checkLabels are the labels of every metric of a check
+var checkLabels = []string{"token", "alias", "url", "type"}
+
+// Options configures an Exporter
+type Options struct {
+	// Interval between two refreshes of the checks and their metrics. Scrapes are served from
+	// the last refresh, so that their frequency does not change the number of API calls.
+	RefreshInterval time.Duration
+	// Period over which the metrics of the checks are aggregated by the API, ending at the
+	// refresh
+	MetricsPeriod time.Duration
+	// Number of checks whose metrics are read concurrently
+	Workers int
+}
+
+// snapshot is the state of the checks at a refresh
+type snapshot struct {
+	checks  []updown.Check
+	metrics map[string]updown.Metrics
+}
+
+// Exporter is a prometheus.Collector of the checks of an updown.io account
+type Exporter struct {
+	client *updown.Client
+	opts   Options
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	snapshot *snapshot
+
+	refreshMu sync.Mutex
+
+	refreshErrors   prometheus.Counter
+	refreshDuration prometheus.Gauge
+	lastRefresh     prometheus.Gauge
+}
+
+// New returns an Exporter reading the checks with client
+func New(client *updown.Client, opts Options) *Exporter {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	if opts.MetricsPeriod <= 0 {
+		opts.MetricsPeriod = time.Hour
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	return &Exporter{
+		client: client,
+		opts:   opts,
+		now:    time.Now,
+		refreshErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "exporter", Name: "refresh_errors_total",
+			Help: "Number of errors met while reading the checks and their metrics from the API.",
+		}),
+		refreshDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "exporter", Name: "refresh_duration_seconds",
+			Help: "Duration of the last refresh of the checks and their metrics.",
+		}),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "exporter", Name: "last_refresh_timestamp_seconds",
+			Help: "Time of the last successful refresh of the list of checks.",
+		}),
+	}
+}
+
+// Run refreshes the checks every RefreshInterval until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(); err != nil {
+			log.Printf("[ERROR] refreshing checks: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reads the checks and the metrics of the enabled ones which are not pulse checks.
+// Checks whose metrics cannot be read are exported without them. It fails only when the checks
+// cannot be listed, keeping the previous snapshot.
+func (e *Exporter) Refresh() error {
+	e.refreshMu.Lock()
+	defer e.refreshMu.Unlock()
+
+	start := e.now()
+	defer func() { e.refreshDuration.Set(e.now().Sub(start).Seconds()) }()
+
+	checks, _, err := e.client.Check.List()
+	if err != nil {
+		e.refreshErrors.Inc()
+		return fmt.Errorf("listing checks: %w", err)
+	}
+
+	s := &snapshot{checks: checks, metrics: e.readMetrics(checks, start)}
+
+	e.mu.Lock()
+	e.snapshot = s
+	e.mu.Unlock()
+	e.lastRefresh.Set(float64(start.Unix()))
+
+	return nil
+}
+
+// readMetrics reads the metrics by location of the enabled checks, except the pulse ones, from
+// Workers goroutines
+func (e *Exporter) readMetrics(checks []updown.Check, to time.Time) map[string]updown.Metrics {
+	from := to.Add(-e.opts.MetricsPeriod).UTC().Format(time.RFC3339)
+
+	tokens := make(chan string)
+	metrics := map[string]updown.Metrics{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < e.opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for token := range tokens {
+				m, _, err := e.client.Metric.List(token, "host", from, to.UTC().Format(time.RFC3339))
+				if err != nil {
+					e.refreshErrors.Inc()
+					log.Printf("[WARN] reading metrics of check %s: %s", token, err)
+					continue
+				}
+				mu.Lock()
+				metrics[token] = m
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, c := range checks {
+		// Pulse checks are not requested from the locations, and have no metrics
+		if c.Enabled && c.Type != "pulse" {
+			tokens <- c.Token
+		}
+	}
+	close(tokens)
+	wg.Wait()
+
+	return metrics
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range descs {
+		ch <- d
+	}
+	e.refreshErrors.Describe(ch)
+	e.refreshDuration.Describe(ch)
+	e.lastRefresh.Describe(ch)
+}
+
+// Collect implements prometheus.Collector. The checks are refreshed first if they were never
+// read.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	s := e.snapshot
+	e.mu.RUnlock()
+
+	if s == nil {
+		if err := e.Refresh(); err != nil {
+			log.Printf("[ERROR] refreshing checks: %s", err)
+		}
+		e.mu.RLock()
+		s = e.snapshot
+		e.mu.RUnlock()
+	}
+
+	if s != nil {
+		for _, c := range s.checks {
+			collectCheck(ch, c, s.metrics[c.Token])
+		}
+	}
+
+	e.refreshErrors.Collect(ch)
+	e.refreshDuration.Collect(ch)
+	e.lastRefresh.Collect(ch)
+}
@@ -0,0 +1,125 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup returns an exporter reading from a fake API, and the number of requests made to it
+func setup(t *testing.T) (*Exporter, *int32) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprint(w, `[
+			{"token":"abcd","url":"https://example.com","alias":"web","type":"https","down":false,"enabled":true,
+			 "uptime":99.5,"apdex_t":0.5,"last_status":200,"last_check_at":"2026-10-18T10:00:00Z",
+			 "ssl":{"tested_at":"2026-10-18T09:00:00Z","valid":true}},
+			{"token":"dddd","url":"https://pulse.updown.io/dddd/secret","alias":"backup","type":"pulse","down":true,"enabled":true,"uptime":90},
+			{"token":"off","url":"https://old.example.com","type":"http","enabled":false},
+			{"token":"eeee","url":"https://api.example.com","alias":"api","type":"https","enabled":true,"uptime":100}
+		]`)
+	})
+	mux.HandleFunc("/checks/abcd/metrics", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "host", r.URL.Query().Get("group"))
+		assert.Equal(t, "2026-10-18T09:00:00Z", r.URL.Query().Get("from"))
+		fmt.Fprint(w, `{"mia":{"apdex":0.98,"host":{"city":"Miami"},
+			"timings":{"namelookup":5,"connection":20,"handshake":40,"response":100,"total":165},
+			"requests":{"samples":100,"failures":2,"satisfied":95,"tolerated":3,
+			 "by_response_time":{"under125":10,"under250":80,"under500":95,"under1000":97,"under2000":98,"under4000":98}}}}`)
+	})
+	mux.HandleFunc("/checks/dddd/metrics", func(http.ResponseWriter, *http.Request) {
+		t.Error("the metrics of pulse checks are read")
+	})
+	mux.HandleFunc("/checks/eeee/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := updown.NewClient("key", nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	e := New(client, Options{})
+	e.now = func() time.Time { return time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC) }
+	return e, &calls
+}
+
+func TestExporter_Collect(t *testing.T) {
+	e, _ := setup(t)
+
+	expected := `
+# HELP updown_check_up Whether the check is up (1) or down (0).
+# TYPE updown_check_up gauge
+updown_check_up{alias="backup",token="dddd",type="pulse",url="https://pulse.updown.io/dddd/<redacted>"} 0
+updown_check_up{alias="web",token="abcd",type="https",url="https://example.com"} 1
+updown_check_up{alias="",token="off",type="http",url="https://old.example.com"} 1
+updown_check_up{alias="api",token="eeee",type="https",url="https://api.example.com"} 1
+# HELP updown_check_uptime_ratio Uptime of the check over the last 30 days, between 0 and 1.
+# TYPE updown_check_uptime_ratio gauge
+updown_check_uptime_ratio{alias="backup",token="dddd",type="pulse",url="https://pulse.updown.io/dddd/<redacted>"} 0.9
+updown_check_uptime_ratio{alias="web",token="abcd",type="https",url="https://example.com"} 0.995
+updown_check_uptime_ratio{alias="",token="off",type="http",url="https://old.example.com"} 0
+updown_check_uptime_ratio{alias="api",token="eeee",type="https",url="https://api.example.com"} 1
+# HELP updown_check_last_status HTTP status code of the last request of the check.
+# TYPE updown_check_last_status gauge
+updown_check_last_status{alias="web",token="abcd",type="https",url="https://example.com"} 200
+# HELP updown_check_ssl_valid Whether the SSL certificate of the check was valid at its last test.
+# TYPE updown_check_ssl_valid gauge
+updown_check_ssl_valid{alias="web",token="abcd",type="https",url="https://example.com"} 1
+# HELP updown_check_timing_seconds Average duration of each phase of the requests of the check over the metrics period, by location.
+# TYPE updown_check_timing_seconds gauge
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="connection",token="abcd",type="https",url="https://example.com"} 0.02
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="handshake",token="abcd",type="https",url="https://example.com"} 0.04
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="namelookup",token="abcd",type="https",url="https://example.com"} 0.005
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="redirect",token="abcd",type="https",url="https://example.com"} 0
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="response",token="abcd",type="https",url="https://example.com"} 0.1
+updown_check_timing_seconds{alias="web",city="Miami",location="mia",phase="total",token="abcd",type="https",url="https://example.com"} 0.165
+# HELP updown_check_requests_by_response_time Number of requests of the check over the metrics period which took at most max_seconds seconds, by location.
+# TYPE updown_check_requests_by_response_time gauge
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="+Inf",token="abcd",type="https",url="https://example.com"} 100
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="0.125",token="abcd",type="https",url="https://example.com"} 10
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="0.25",token="abcd",type="https",url="https://example.com"} 80
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="0.5",token="abcd",type="https",url="https://example.com"} 95
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="1",token="abcd",type="https",url="https://example.com"} 97
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="2",token="abcd",type="https",url="https://example.com"} 98
+updown_check_requests_by_response_time{alias="web",city="Miami",location="mia",max_seconds="4",token="abcd",type="https",url="https://example.com"} 98
+# HELP updown_exporter_refresh_errors_total Number of errors met while reading the checks and their metrics from the API.
+# TYPE updown_exporter_refresh_errors_total counter
+updown_exporter_refresh_errors_total 1
+`
+	require.NoError(t, testutil.CollectAndCompare(e, strings.NewReader(expected),
+		"updown_check_up", "updown_check_uptime_ratio", "updown_check_last_status", "updown_check_ssl_valid",
+		"updown_check_timing_seconds", "updown_check_requests_by_response_time", "updown_exporter_refresh_errors_total"))
+}
+
+func TestExporter_Cache(t *testing.T) {
+	e, calls := setup(t)
+
+	// The first scrape reads the checks, and the metrics of the two enabled ones which are not
+	// pulse checks
+	assert.Equal(t, 4, testutil.CollectAndCount(e, "updown_check_enabled"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	// The next ones are served from the last refresh
+	for i := 0; i < 5; i++ {
+		testutil.CollectAndCount(e)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	require.NoError(t, e.Refresh())
+	assert.Equal(t, int32(6), atomic.LoadInt32(calls))
+}
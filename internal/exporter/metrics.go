@@ -0,0 +1,100 @@
+// Package exporter exposes the status and the metrics of updown.io checks to Prometheus.
+package exporter
+
+import (
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// responseTimeBuckets are the upper bounds in seconds of the response time buckets of the API
+var responseTimeBuckets = []string{"0.125", "0.25", "0.5", "1", "2", "4"}
+
+func newCheckDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "check", name), help, append(append([]string{}, checkLabels...), labels...), nil)
+}
+
+var (
+	upDesc           = newCheckDesc("up", "Whether the check is up (1) or down (0).")
+	enabledDesc      = newCheckDesc("enabled", "Whether the check is enabled.")
+	uptimeDesc       = newCheckDesc("uptime_ratio", "Uptime of the check over the last 30 days, between 0 and 1.")
+	apdexTDesc       = newCheckDesc("apdex_threshold_seconds", "APDEX threshold of the check.")
+	lastStatusDesc   = newCheckDesc("last_status", "HTTP status code of the last request of the check.")
+	lastCheckDesc    = newCheckDesc("last_check_timestamp_seconds", "Time of the last request of the check.")
+	sslValidDesc     = newCheckDesc("ssl_valid", "Whether the SSL certificate of the check was valid at its last test.")
+	apdexDesc        = newCheckDesc("apdex", "APDEX score of the check over the metrics period, by location.", "location", "city")
+	timingDesc       = newCheckDesc("timing_seconds", "Average duration of each phase of the requests of the check over the metrics period, by location.", "location", "city", "phase")
+	requestsDesc     = newCheckDesc("requests", "Number of requests of the check over the metrics period by result, by location.", "location", "city", "result")
+	responseTimeDesc = newCheckDesc("requests_by_response_time", "Number of requests of the check over the metrics period which took at most max_seconds seconds, by location.", "location", "city", "max_seconds")
+
+	descs = []*prometheus.Desc{
+		upDesc, enabledDesc, uptimeDesc, apdexTDesc, lastStatusDesc, lastCheckDesc, sslValidDesc,
+		apdexDesc, timingDesc, requestsDesc, responseTimeDesc,
+	}
+)
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// collectCheck sends the metrics of a check, and of its locations
+func collectCheck(ch chan<- prometheus.Metric, c updown.Check, metrics updown.Metrics) {
+	// The URL of pulse checks holds their secret
+	labels := []string{c.Token, c.Alias, updown.RedactString(c.URL), c.Type}
+	gauge := func(desc *prometheus.Desc, v float64, extra ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, append(append([]string{}, labels...), extra...)...)
+	}
+
+	gauge(upDesc, boolToFloat(!c.Down))
+	gauge(enabledDesc, boolToFloat(c.Enabled))
+	gauge(uptimeDesc, c.Uptime/100)
+	if c.Apdex > 0 {
+		gauge(apdexTDesc, c.Apdex)
+	}
+	if c.LastStatus != 0 {
+		gauge(lastStatusDesc, float64(c.LastStatus))
+	}
+	if t, err := time.Parse(time.RFC3339, c.LastCheckAt); err == nil {
+		gauge(lastCheckDesc, float64(t.Unix()))
+	}
+	if c.SSL.TestedAt != "" {
+		gauge(sslValidDesc, boolToFloat(c.SSL.Valid))
+	}
+
+	for location, m := range metrics {
+		gauge(apdexDesc, m.Apdex, location, m.Host.City)
+
+		for phase, ms := range map[string]int{
+			"redirect":   m.Timings.Redirect,
+			"namelookup": m.Timings.NameLookup,
+			"connection": m.Timings.Connection,
+			"handshake":  m.Timings.Handshake,
+			"response":   m.Timings.Response,
+			"total":      m.Timings.Total,
+		} {
+			gauge(timingDesc, float64(ms)/1000, location, m.Host.City, phase)
+		}
+
+		r := m.Requests
+		for result, n := range map[string]int{
+			"samples":   r.Samples,
+			"failures":  r.Failures,
+			"satisfied": r.Satisfied,
+			"tolerated": r.Tolerated,
+		} {
+			gauge(requestsDesc, float64(n), location, m.Host.City, result)
+		}
+
+		// Gauges rather than a histogram, as the API gives no sum of the response times, labelled
+		// with max_seconds rather than le so that histogram_quantile is not applied to them
+		rt := r.ResponseTime
+		for i, n := range []int{rt.Under125, rt.Under250, rt.Under500, rt.Under1000, rt.Under2000, rt.Under4000} {
+			gauge(responseTimeDesc, float64(n), location, m.Host.City, responseTimeBuckets[i])
+		}
+		gauge(responseTimeDesc, float64(r.Samples), location, m.Host.City, "+Inf")
+	}
+}
@@ -4,14 +4,13 @@ package provider
 import (
 	"fmt"
 
-	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func nodesDataSource() *schema.Resource {
 	return &schema.Resource{
 		Description: "`updown_nodes` data source can be used to retrieve the IP addresses of their servers.",
-		Read:        nodesList,
+		ReadContext: withRequestContext(nodesList),
 
 		Schema: map[string]*schema.Schema{
 			"ipv4": {
@@ -35,7 +34,7 @@ func nodesDataSource() *schema.Resource {
 }
 
 func nodesList(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	client := meta.(*providerConfig).client
 
 	ipv4, _, err := client.Node.ListIPv4()
 	if err != nil {
@@ -0,0 +1,95 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"fmt"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func recipientDataSource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_recipient` data source can be used to look up a single recipient, such as an integration set up from the web UI, by its ID or by its type and name or value.",
+		ReadContext: withRequestContext(recipientLookup),
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "ID of the recipient.",
+				ExactlyOneOf: []string{"id", "type"},
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Type of the recipient, any integration type returned by the API (email, webhook, slack, telegram, etc.).",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the recipient to look up with `type`.",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Value of the recipient to look up with `type`.",
+			},
+			"immutable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the recipient is managed by updown.io or the web UI and cannot be removed.",
+			},
+		},
+	}
+}
+
+func recipientLookup(d *schema.ResourceData, meta interface{}) error {
+	recipients, err := meta.(*providerConfig).listRecipients()
+	if err != nil {
+		return fmt.Errorf("reading recipients from the API: %w", err)
+	}
+
+	id := d.Get("id").(string)
+	recipientType := updown.RecipientType(d.Get("type").(string))
+	name, value := d.Get("name").(string), d.Get("value").(string)
+
+	var found []updown.Recipient
+	for _, r := range recipients {
+		switch {
+		case id != "":
+			if r.ID != id {
+				continue
+			}
+		case r.Type != recipientType,
+			name != "" && r.Name != name,
+			value != "" && !recipientMatches(r, recipientType, value):
+			continue
+		}
+		found = append(found, r)
+	}
+
+	switch len(found) {
+	case 0:
+		return fmt.Errorf("no recipient found matching the given arguments")
+	case 1:
+	default:
+		return fmt.Errorf("%d recipients found matching the given arguments, narrow down the search with name or value", len(found))
+	}
+
+	d.SetId(found[0].ID)
+	for k, v := range flattenRecipient(found[0]) {
+		if k == "id" {
+			continue
+		}
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
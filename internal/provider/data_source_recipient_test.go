@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handleRecipients(mux *http.ServeMux) {
+	mux.HandleFunc("/recipients", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[
+			{"id":"email:1","type":"email","name":"ops@example.com","value":"ops@example.com"},
+			{"id":"slack:2","type":"slack","name":"#alerts","immutable":true},
+			{"id":"slack:3","type":"slack","name":"#incidents","immutable":true},
+			{"id":"pagerduty:4","type":"pagerduty","name":"On-call"}
+		]`)
+	})
+}
+
+func TestRecipientLookup(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleRecipients(mux)
+	meta := &providerConfig{client: client}
+
+	for _, tc := range []struct {
+		config map[string]interface{}
+		id     string
+	}{
+		{map[string]interface{}{"id": "pagerduty:4"}, "pagerduty:4"},
+		{map[string]interface{}{"type": "slack", "name": "#incidents"}, "slack:3"},
+		{map[string]interface{}{"type": "email", "value": "OPS@example.com"}, "email:1"},
+	} {
+		d := schema.TestResourceDataRaw(t, recipientDataSource().Schema, tc.config)
+		require.NoError(t, recipientLookup(d, meta), tc.config)
+		assert.Equal(t, tc.id, d.Id())
+	}
+
+	d := schema.TestResourceDataRaw(t, recipientDataSource().Schema, map[string]interface{}{"type": "slack", "name": "#alerts"})
+	require.NoError(t, recipientLookup(d, meta))
+	assert.Equal(t, true, d.Get("immutable"))
+
+	d = schema.TestResourceDataRaw(t, recipientDataSource().Schema, map[string]interface{}{"type": "slack"})
+	assert.EqualError(t, recipientLookup(d, meta), "2 recipients found matching the given arguments, narrow down the search with name or value")
+}
+
+func TestRecipientsList(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleRecipients(mux)
+	meta := &providerConfig{client: client}
+
+	d := schema.TestResourceDataRaw(t, recipientsDataSource().Schema, map[string]interface{}{})
+	require.NoError(t, recipientsList(d, meta))
+	assert.Len(t, d.Get("recipients"), 4)
+
+	d = schema.TestResourceDataRaw(t, recipientsDataSource().Schema, map[string]interface{}{"type": "slack"})
+	require.NoError(t, recipientsList(d, meta))
+	assert.Len(t, d.Get("recipients"), 2)
+	assert.Equal(t, "#alerts", d.Get("recipients.0.name"))
+}
@@ -0,0 +1,94 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"fmt"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func recipientsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_recipients` data source can be used to list the recipients of the account, including the integrations set up from the web UI (slack, telegram, zapier, pagerduty, etc.).",
+		ReadContext: withRequestContext(recipientsList),
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the recipients of this type.",
+			},
+			"recipients": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Recipients of the account.",
+				Elem: &schema.Resource{
+					Schema: recipientDataSchema(),
+				},
+			},
+		},
+	}
+}
+
+// recipientDataSchema describes a recipient returned by the recipients data sources
+func recipientDataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the recipient, to use in the `recipients` of checks and pulses.",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Type of the recipient, any integration type returned by the API.",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the recipient.",
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Value of the recipient (email address, phone number or URL), empty for most web UI integrations.",
+		},
+		"immutable": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the recipient is managed by updown.io or the web UI and cannot be removed.",
+		},
+	}
+}
+
+func flattenRecipient(r updown.Recipient) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        r.ID,
+		"type":      string(r.Type),
+		"name":      r.Name,
+		"value":     r.Value,
+		"immutable": r.Immutable,
+	}
+}
+
+func recipientsList(d *schema.ResourceData, meta interface{}) error {
+	recipients, err := meta.(*providerConfig).listRecipients()
+	if err != nil {
+		return fmt.Errorf("reading recipients from the API: %w", err)
+	}
+
+	recipientType := d.Get("type").(string)
+
+	res := []interface{}{}
+	for _, r := range recipients {
+		if recipientType != "" && string(r.Type) != recipientType {
+			continue
+		}
+		res = append(res, flattenRecipient(r))
+	}
+
+	d.SetId("updown.io/recipients/" + recipientType)
+
+	return d.Set("recipients", res)
+}
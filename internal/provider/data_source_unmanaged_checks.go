@@ -0,0 +1,112 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func unmanagedChecksDataSource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_unmanaged_checks` data source lists the checks and pulses whose alias does not carry the provider `alias_prefix`, " +
+			"i.e. the ones which were not created or imported with Terraform, so that they can be cleaned up or imported.",
+		ReadContext: withRequestContext(unmanagedChecksList),
+
+		Schema: map[string]*schema.Schema{
+			"alias_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Prefix marking the managed checks. Defaults to the provider `alias_prefix`.",
+			},
+			"tokens": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Tokens of the unmanaged checks.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"checks": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Unmanaged checks.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"token": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Token of the check, to import it.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the check, `pulse` for pulses.",
+						},
+						"alias": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Alias of the check.",
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "URL of the check.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the check is enabled.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func unmanagedChecksList(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*providerConfig)
+
+	prefix := d.Get("alias_prefix").(string)
+	if prefix == "" {
+		prefix = config.aliasPrefix
+	}
+	if prefix == "" {
+		return fmt.Errorf("alias_prefix must be set on the data source or the provider")
+	}
+
+	checks, err := config.listChecks()
+	if err != nil {
+		return fmt.Errorf("reading checks from the API: %w", err)
+	}
+
+	tokens, res := []string{}, []interface{}{}
+	for _, c := range checks {
+		if _, marked := unmarkAlias(prefix, c.Alias); marked {
+			continue
+		}
+		tokens = append(tokens, c.Token)
+		res = append(res, map[string]interface{}{
+			"token":   c.Token,
+			"type":    c.Type,
+			"alias":   c.Alias,
+			"url":     c.URL,
+			"enabled": c.Enabled,
+		})
+	}
+
+	d.SetId("updown.io/unmanaged_checks/" + prefix)
+
+	for k, v := range map[string]interface{}{
+		"alias_prefix": prefix,
+		"tokens":       tokens,
+		"checks":       res,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
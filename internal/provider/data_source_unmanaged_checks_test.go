@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmanagedChecksList(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[
+			{"token":"aaaa","type":"https","alias":"[tf] Website","url":"https://example.com"},
+			{"token":"bbbb","type":"https","alias":"Legacy","url":"https://example.org","enabled":true},
+			{"token":"cccc","type":"http","url":"http://example.net"},
+			{"token":"dddd","type":"pulse","alias":"[tf] Backup"}
+		]`)
+	})
+
+	d := schema.TestResourceDataRaw(t, unmanagedChecksDataSource().Schema, map[string]interface{}{})
+	require.NoError(t, unmanagedChecksList(d, &providerConfig{client: client, aliasPrefix: "[tf] "}))
+	assert.Equal(t, []interface{}{"bbbb", "cccc"}, d.Get("tokens"))
+	assert.Equal(t, map[string]interface{}{
+		"token":   "bbbb",
+		"type":    "https",
+		"alias":   "Legacy",
+		"url":     "https://example.org",
+		"enabled": true,
+	}, d.Get("checks.0"))
+
+	d = schema.TestResourceDataRaw(t, unmanagedChecksDataSource().Schema, map[string]interface{}{"alias_prefix": "Leg"})
+	require.NoError(t, unmanagedChecksList(d, &providerConfig{client: client, aliasPrefix: "[tf] "}))
+	assert.Equal(t, []interface{}{"aaaa", "cccc", "dddd"}, d.Get("tokens"))
+
+	d = schema.TestResourceDataRaw(t, unmanagedChecksDataSource().Schema, map[string]interface{}{})
+	assert.EqualError(t, unmanagedChecksList(d, &providerConfig{client: client}), "alias_prefix must be set on the data source or the provider")
+}
@@ -0,0 +1,170 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultApdex is the APDEX threshold of checks when neither the resource nor the provider
+// defaults set one
+const defaultApdex = 0.5
+
+// resourceDefaults holds the values of the provider `defaults` block
+type resourceDefaults struct {
+	RecipientIDs      []string
+	DisabledLocations []string
+	CustomHeaders     map[string]string
+	Apdex             float64
+}
+
+func defaultsResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"recipients": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Recipient IDs selected on checks and pulses which do not set `recipients`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"disabled_locations": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Locations disabled on checks which do not set `disabled_locations`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"custom_headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "HTTP headers added to every check. Headers set on a check override the default ones with the same name.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"apdex_t": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "APDEX threshold in seconds of checks which do not set `apdex_t`.",
+			},
+		},
+	}
+}
+
+func expandResourceDefaults(l []interface{}) resourceDefaults {
+	defaults := resourceDefaults{}
+	if len(l) == 0 || l[0] == nil {
+		return defaults
+	}
+
+	m := l[0].(map[string]interface{})
+	if v, ok := m["recipients"].(*schema.Set); ok {
+		defaults.RecipientIDs = setToStringSlice(v)
+	}
+	if v, ok := m["disabled_locations"].(*schema.Set); ok {
+		defaults.DisabledLocations = setToStringSlice(v)
+	}
+	if v, ok := m["custom_headers"].(map[string]interface{}); ok && len(v) > 0 {
+		defaults.CustomHeaders = map[string]string{}
+		for k, h := range v {
+			defaults.CustomHeaders[k] = h.(string)
+		}
+	}
+	if v, ok := m["apdex_t"].(float64); ok {
+		defaults.Apdex = v
+	}
+
+	return defaults
+}
+
+// defaultsFrom returns the provider defaults, meta can be nil when the provider is not configured yet
+func defaultsFrom(meta interface{}) resourceDefaults {
+	if c, ok := meta.(*providerConfig); ok {
+		return c.defaults
+	}
+	return resourceDefaults{}
+}
+
+// checkCustomizeDiff plans the effective values of the attributes of a check which fall back on
+// the provider defaults, so that the plan shows what will be sent to the API.
+func checkCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	defaults := defaultsFrom(meta)
+
+	apdex := defaults.Apdex
+	if apdex == 0 {
+		apdex = defaultApdex
+	}
+
+	for k, v := range map[string]interface{}{
+		"apdex_t":            apdex,
+		"recipients":         defaults.RecipientIDs,
+		"disabled_locations": defaults.DisabledLocations,
+	} {
+		if err := setNewUnlessConfigured(d, k, v); err != nil {
+			return err
+		}
+	}
+
+	return setCustomHeaders(d, defaults.CustomHeaders)
+}
+
+// pulseCustomizeDiff plans the effective recipients of a pulse
+func pulseCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return setNewUnlessConfigured(d, "recipients", defaultsFrom(meta).RecipientIDs)
+}
+
+// setNewUnlessConfigured plans value for key when the attribute is absent from the configuration
+func setNewUnlessConfigured(d *schema.ResourceDiff, key string, value interface{}) error {
+	if isConfigured(d, key) {
+		return nil
+	}
+	return d.SetNew(key, value)
+}
+
+// setCustomHeaders plans the default custom headers merged with the ones of the resource
+func setCustomHeaders(d *schema.ResourceDiff, defaults map[string]string) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().HasAttribute("custom_headers") {
+		return nil
+	}
+
+	configured := config.GetAttr("custom_headers")
+	if !configured.IsWhollyKnown() {
+		return nil
+	}
+
+	headers := map[string]interface{}{}
+	for k, v := range defaults {
+		headers[k] = v
+	}
+	if !configured.IsNull() {
+		for k, v := range configured.AsValueMap() {
+			if !v.IsNull() {
+				headers[k] = v.AsString()
+			}
+		}
+	}
+
+	// The API may return the header names with a different case, keep the stored ones when
+	// nothing else changed so that it does not show up as a diff.
+	state, _ := d.GetChange("custom_headers")
+	if stateHeaders, ok := state.(map[string]interface{}); ok && equivalentHeaders(stateHeaders, headers) {
+		return d.SetNew("custom_headers", stateHeaders)
+	}
+
+	return d.SetNew("custom_headers", headers)
+}
+
+// isConfigured tells if the attribute is set in the configuration of the resource. When the
+// configuration is not available, attributes are considered as configured.
+func isConfigured(d *schema.ResourceDiff, key string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().HasAttribute(key) {
+		return true
+	}
+	return !config.GetAttr(key).IsNull()
+}
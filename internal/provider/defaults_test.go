@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plan runs the diff of a resource, CustomizeDiff included, for a configuration whose missing
+// attributes are null, and returns the planned resource data. A non nil state plans an update.
+func plan(t *testing.T, r *schema.Resource, config map[string]cty.Value, state map[string]string, meta interface{}) *schema.ResourceData {
+	s, diff := planDiff(t, r, config, state, meta)
+	d, err := schema.InternalMap(r.SchemaMap()).Data(s, diff)
+	require.NoError(t, err)
+	return d
+}
+
+// planDiff is plan returning the prior state and the diff
+func planDiff(t *testing.T, r *schema.Resource, config map[string]cty.Value, state map[string]string, meta interface{}) (*terraform.InstanceState, *terraform.InstanceDiff) {
+	attrs := map[string]cty.Value{}
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		if v, ok := config[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = cty.NullVal(ty)
+		}
+	}
+	raw := cty.ObjectVal(attrs)
+
+	s := &terraform.InstanceState{RawConfig: raw}
+	if state != nil {
+		s.ID = "aaaa"
+		s.Attributes = state
+	}
+
+	diff, err := r.Diff(context.Background(), s, terraform.NewResourceConfigShimmed(raw, r.CoreConfigSchema()), meta)
+	require.NoError(t, err)
+	return s, diff
+}
+
+func stringSet(values ...string) cty.Value {
+	if len(values) == 0 {
+		return cty.SetValEmpty(cty.String)
+	}
+	vals := []cty.Value{}
+	for _, v := range values {
+		vals = append(vals, cty.StringVal(v))
+	}
+	return cty.SetVal(vals)
+}
+
+func TestExpandResourceDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, New()().Schema, map[string]interface{}{
+		"defaults": []interface{}{map[string]interface{}{
+			"recipients":         []interface{}{"email:1"},
+			"disabled_locations": []interface{}{"sin"},
+			"custom_headers":     map[string]interface{}{"X-Env": "prod"},
+			"apdex_t":            1.0,
+		}},
+	})
+
+	assert.Equal(t, resourceDefaults{
+		RecipientIDs:      []string{"email:1"},
+		DisabledLocations: []string{"sin"},
+		CustomHeaders:     map[string]string{"X-Env": "prod"},
+		Apdex:             1.0,
+	}, expandResourceDefaults(d.Get("defaults").([]interface{})))
+	assert.Equal(t, resourceDefaults{}, expandResourceDefaults(nil))
+}
+
+func TestCheckCustomizeDiff(t *testing.T) {
+	meta := &providerConfig{defaults: resourceDefaults{
+		RecipientIDs:      []string{"email:1"},
+		DisabledLocations: []string{"sin"},
+		CustomHeaders:     map[string]string{"X-Env": "prod", "X-Team": "ops"},
+		Apdex:             1.0,
+	}}
+	url := map[string]cty.Value{"url": cty.StringVal("https://example.com")}
+	with := func(extra map[string]cty.Value) map[string]cty.Value {
+		config := map[string]cty.Value{}
+		for k, v := range url {
+			config[k] = v
+		}
+		for k, v := range extra {
+			config[k] = v
+		}
+		return config
+	}
+
+	for name, tc := range map[string]struct {
+		config     map[string]cty.Value
+		state      map[string]string
+		meta       interface{}
+		apdex      float64
+		recipients []string
+		locations  []string
+		headers    map[string]interface{}
+	}{
+		"defaults applied when unset": {
+			config:     url,
+			meta:       meta,
+			apdex:      1.0,
+			recipients: []string{"email:1"},
+			locations:  []string{"sin"},
+			headers:    map[string]interface{}{"X-Env": "prod", "X-Team": "ops"},
+		},
+		"resource overrides": {
+			config: with(map[string]cty.Value{
+				"apdex_t":            cty.NumberFloatVal(2),
+				"recipients":         stringSet("email:2"),
+				"disabled_locations": stringSet("bhs"),
+				"custom_headers":     cty.MapVal(map[string]cty.Value{"X-Team": cty.StringVal("billing")}),
+			}),
+			meta:       meta,
+			apdex:      2,
+			recipients: []string{"email:2"},
+			locations:  []string{"bhs"},
+			headers:    map[string]interface{}{"X-Env": "prod", "X-Team": "billing"},
+		},
+		"explicitly empty lists override the defaults": {
+			config: with(map[string]cty.Value{
+				"recipients":         stringSet(),
+				"disabled_locations": stringSet(),
+			}),
+			meta:       meta,
+			apdex:      1.0,
+			recipients: []string{},
+			locations:  []string{},
+			headers:    map[string]interface{}{"X-Env": "prod", "X-Team": "ops"},
+		},
+		"header names differing only by case keep the state": {
+			config: url,
+			state: map[string]string{
+				"id":                    "aaaa",
+				"url":                   "https://example.com",
+				"custom_headers.%":      "2",
+				"custom_headers.x-env":  "prod",
+				"custom_headers.x-team": "ops",
+			},
+			meta:       meta,
+			apdex:      1.0,
+			recipients: []string{"email:1"},
+			locations:  []string{"sin"},
+			headers:    map[string]interface{}{"x-env": "prod", "x-team": "ops"},
+		},
+		"header values differing are planned": {
+			config: url,
+			state: map[string]string{
+				"id":                   "aaaa",
+				"url":                  "https://example.com",
+				"custom_headers.%":     "1",
+				"custom_headers.x-env": "staging",
+			},
+			meta:       meta,
+			apdex:      1.0,
+			recipients: []string{"email:1"},
+			locations:  []string{"sin"},
+			headers:    map[string]interface{}{"X-Env": "prod", "X-Team": "ops"},
+		},
+		"nil meta before configure": {
+			config:     url,
+			apdex:      defaultApdex,
+			recipients: []string{},
+			locations:  []string{},
+			headers:    map[string]interface{}{},
+		},
+	} {
+		d := plan(t, checkResource(), tc.config, tc.state, tc.meta)
+		assert.Equal(t, tc.apdex, d.Get("apdex_t"), name)
+		assert.ElementsMatch(t, tc.recipients, setToStringSlice(d.Get("recipients").(*schema.Set)), name)
+		assert.ElementsMatch(t, tc.locations, setToStringSlice(d.Get("disabled_locations").(*schema.Set)), name)
+		assert.Equal(t, tc.headers, d.Get("custom_headers"), name)
+	}
+}
+
+func TestPulseCustomizeDiff(t *testing.T) {
+	meta := &providerConfig{defaults: resourceDefaults{RecipientIDs: []string{"email:1"}}}
+	period := map[string]cty.Value{"period": cty.NumberIntVal(3600)}
+
+	d := plan(t, pulseResource(), period, nil, meta)
+	assert.Equal(t, []string{"email:1"}, setToStringSlice(d.Get("recipients").(*schema.Set)))
+
+	d = plan(t, pulseResource(), map[string]cty.Value{
+		"period":     cty.NumberIntVal(3600),
+		"recipients": stringSet(),
+	}, nil, meta)
+	assert.Empty(t, setToStringSlice(d.Get("recipients").(*schema.Set)))
+
+	d = plan(t, pulseResource(), period, nil, nil)
+	assert.Empty(t, setToStringSlice(d.Get("recipients").(*schema.Set)))
+}
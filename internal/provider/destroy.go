@@ -0,0 +1,81 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Values of on_destroy
+const (
+	onDestroyDelete  = "delete"
+	onDestroyDisable = "disable"
+)
+
+// withDestroySchema adds to the schema of checks and pulses the attributes controlling what
+// happens when they are destroyed
+func withDestroySchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["deletion_protection"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Refuse to destroy the check, which would lose its uptime and downtime history. It must be set to false, and applied, before the check can be destroyed.",
+	}
+	s["on_destroy"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      onDestroyDelete,
+		Description:  "What to do when the resource is destroyed: 'delete' the check, or 'disable' it to keep its history.",
+		ValidateFunc: validation.StringInSlice([]string{onDestroyDelete, onDestroyDisable}, false),
+	}
+	s["disabled_alias_suffix"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Suffix appended to the alias of the check when it is disabled on destroy, such as ' (decommissioned)'.",
+	}
+	return s
+}
+
+// destroyCheck deletes a check or a pulse, or disables it when on_destroy is 'disable'. kind
+// names the resource in error messages.
+func destroyCheck(d *schema.ResourceData, meta interface{}, kind string) error {
+	config := meta.(*providerConfig)
+
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("%s %s is protected against deletion, set deletion_protection to false and apply before destroying it", kind, d.Id())
+	}
+
+	if d.Get("on_destroy").(string) == onDestroyDisable {
+		config.checkLocks.Lock(d.Id())
+		defer config.checkLocks.Unlock(d.Id())
+
+		check, _, err := config.client.Check.Get(d.Id())
+		if err != nil {
+			return fmt.Errorf("reading %s from the API: %w", kind, err)
+		}
+
+		payload := checkItemFromCheck(check)
+		payload.Enabled = false
+		if suffix := d.Get("disabled_alias_suffix").(string); suffix != "" && !strings.HasSuffix(payload.Alias, suffix) {
+			payload.Alias += suffix
+		}
+		if _, _, err := config.client.Check.Update(d.Id(), payload); err != nil {
+			return fmt.Errorf("disabling %s with the API: %w", kind, err)
+		}
+		return nil
+	}
+
+	deleted, _, err := config.client.Check.Remove(d.Id())
+	if err != nil {
+		return fmt.Errorf("removing %s from the API: %w", kind, err)
+	}
+
+	if !deleted {
+		return fmt.Errorf("%s couldn't be deleted", kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestroyCheck(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	var updates []updown.CheckItem
+	var removed bool
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			var item updown.CheckItem
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			updates = append(updates, item)
+		case "DELETE":
+			removed = true
+			fmt.Fprint(w, `{"deleted":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"token":"aaaa","type":"https","url":"https://example.com","alias":"Website","enabled":true,"period":60}`)
+	})
+
+	data := func(raw map[string]interface{}) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, checkResource().Schema, raw)
+		d.SetId("aaaa")
+		return d
+	}
+
+	err := destroyCheck(data(map[string]interface{}{"deletion_protection": true}), meta, "check")
+	assert.EqualError(t, err, "check aaaa is protected against deletion, set deletion_protection to false and apply before destroying it")
+	assert.Empty(t, updates)
+	assert.False(t, removed)
+
+	require.NoError(t, destroyCheck(data(map[string]interface{}{
+		"on_destroy":            "disable",
+		"disabled_alias_suffix": " (decommissioned)",
+	}), meta, "check"))
+	require.Len(t, updates, 1)
+	assert.False(t, updates[0].Enabled)
+	assert.Equal(t, "Website (decommissioned)", updates[0].Alias)
+	assert.Equal(t, "https://example.com", updates[0].URL)
+	assert.Equal(t, 60, updates[0].Period)
+	assert.False(t, removed)
+
+	require.NoError(t, destroyCheck(data(map[string]interface{}{}), meta, "check"))
+	assert.True(t, removed)
+}
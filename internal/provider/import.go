@@ -0,0 +1,232 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Prefixes of the import IDs resolving a check from its alias or URL rather than its token
+const (
+	importPrefixAlias = "alias:"
+	importPrefixURL   = "url:"
+)
+
+// checkImport imports an updown_check from its token, `alias:<name>` or `url:<url>`
+func checkImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	check, err := resolveCheckImportID(meta.(*providerConfig).withContext(ctx), d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if check.Type == "pulse" {
+		return nil, fmt.Errorf("check %s is a pulse check, import it as an updown_pulse resource", check.Token)
+	}
+
+	d.SetId(check.Token)
+	return []*schema.ResourceData{d}, nil
+}
+
+// pulseImport imports an updown_pulse from its token, `alias:<name>` or `url:<url>`. Any of
+// these can be followed by `,<pulse_url>` to provide the unredacted URL of the pulse.
+func pulseImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, pulseURL := d.Id(), ""
+	if i := strings.LastIndex(id, ","); i >= 0 && strings.HasPrefix(id[i+1:], "http") {
+		id, pulseURL = id[:i], id[i+1:]
+	} else if strings.HasPrefix(id, importPrefixURL) && !isRedactedPulseURL(id) {
+		pulseURL = strings.TrimPrefix(id, importPrefixURL)
+	}
+
+	check, err := resolveCheckImportID(meta.(*providerConfig).withContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if check.Type != "pulse" {
+		return nil, fmt.Errorf("check %s is not a pulse check (type: %s), import it as an updown_check resource", check.Token, check.Type)
+	}
+
+	if pulseURL != "" {
+		if isRedactedPulseURL(pulseURL) || !checkURLMatches(check.URL, pulseURL) {
+			return nil, fmt.Errorf("pulse URL given on import does not belong to pulse check %s", check.Token)
+		}
+		if err := d.Set("pulse_url", pulseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	d.SetId(check.Token)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveCheckImportID finds the check designated by an import ID, which is either a token or
+// an alias or URL prefixed with `alias:` or `url:`. The alias is compared with or without the
+// provider alias_prefix. It fails when several checks match.
+func resolveCheckImportID(config *providerConfig, id string) (updown.Check, error) {
+	var (
+		kind  string
+		match func(updown.Check) bool
+	)
+
+	switch {
+	case strings.HasPrefix(id, importPrefixAlias):
+		kind = "alias"
+		alias := strings.TrimPrefix(id, importPrefixAlias)
+		match = func(c updown.Check) bool {
+			unmarked, _ := unmarkAlias(config.aliasPrefix, c.Alias)
+			return c.Alias == alias || unmarked == alias
+		}
+	case strings.HasPrefix(id, importPrefixURL):
+		kind = "URL"
+		u := strings.TrimPrefix(id, importPrefixURL)
+		match = func(c updown.Check) bool { return checkURLMatches(c.URL, u) }
+	default:
+		check, _, err := config.client.Check.Get(id)
+		if err != nil {
+			return updown.Check{}, fmt.Errorf("reading check from the API: %w", err)
+		}
+		return check, nil
+	}
+
+	checks, err := config.listChecks()
+	if err != nil {
+		return updown.Check{}, fmt.Errorf("reading checks from the API: %w", err)
+	}
+
+	var found []updown.Check
+	for _, c := range checks {
+		if match(c) {
+			found = append(found, c)
+		}
+	}
+
+	value := id[strings.Index(id, ":")+1:]
+	switch len(found) {
+	case 0:
+		return updown.Check{}, fmt.Errorf("no check found with %s %q", kind, value)
+	case 1:
+		return found[0], nil
+	default:
+		tokens := make([]string, len(found))
+		for i, c := range found {
+			tokens[i] = c.Token
+		}
+		return updown.Check{}, fmt.Errorf("%d checks found with %s %q (%s), import it by token instead",
+			len(found), kind, value, strings.Join(tokens, ", "))
+	}
+}
+
+// checkURLMatches compares the URL of a check with the one given on import. The API redacts the
+// secret part of pulse URLs, which is then ignored.
+func checkURLMatches(checkURL, u string) bool {
+	if i := strings.Index(checkURL, "<redacted>"); i >= 0 {
+		return strings.HasPrefix(u, checkURL[:i])
+	}
+	return normalizeURL(checkURL) == normalizeURL(u)
+}
+
+// recipientImport imports an updown_recipient from its ID, or from `<type>:<value>` such as
+// `email:ops@example.com` or `webhook:https://example.com/hook`.
+func recipientImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	recipients, err := meta.(*providerConfig).withContext(ctx).listRecipients()
+	if err != nil {
+		return nil, fmt.Errorf("reading recipients from the API: %w", err)
+	}
+
+	recipient, err := resolveRecipientImportID(recipients, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(recipient.ID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveRecipientImportID finds the recipient designated by an import ID. IDs returned by the
+// API look like `email:123456789`, so the ID is looked up first and `<type>:<value>` second.
+func resolveRecipientImportID(recipients []updown.Recipient, id string) (updown.Recipient, error) {
+	for _, r := range recipients {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+
+	i := strings.Index(id, ":")
+	if i < 0 {
+		return updown.Recipient{}, fmt.Errorf("no recipient found with ID %q", id)
+	}
+	recipientType, value := updown.RecipientType(id[:i]), id[i+1:]
+
+	var found []updown.Recipient
+	for _, r := range recipients {
+		if recipientMatches(r, recipientType, value) {
+			found = append(found, r)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return updown.Recipient{}, fmt.Errorf("no %s recipient found with value %q", recipientType, value)
+	case 1:
+		return found[0], nil
+	default:
+		ids := make([]string, len(found))
+		for i, r := range found {
+			ids[i] = r.ID
+		}
+		return updown.Recipient{}, fmt.Errorf("%d %s recipients found with value %q (%s), import it by ID instead",
+			len(found), recipientType, value, strings.Join(ids, ", "))
+	}
+}
+
+// recipientMatches tells if a recipient has the given type and value. The API returns the
+// value of some recipients in their name, and email addresses are case insensitive.
+func recipientMatches(r updown.Recipient, recipientType updown.RecipientType, value string) bool {
+	if r.Type != recipientType {
+		return false
+	}
+
+	actual := r.Value
+	if actual == "" {
+		actual = r.Name
+	}
+	if recipientType == updown.RecipientTypeEmail {
+		return strings.EqualFold(actual, value)
+	}
+	return actual == value
+}
+
+// checkRecipientImport imports an updown_check_recipient from `<check_token>/<recipient_id>`,
+// where the check can also be designated by `alias:<name>` or `url:<url>`
+func checkRecipientImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	i := strings.LastIndex(d.Id(), "/")
+	if i < 0 {
+		return nil, fmt.Errorf("unexpected ID %q, expected <check_token>/<recipient_id>", d.Id())
+	}
+	// Recipient IDs do not contain slashes, unlike the URLs of checks
+	id, recipientID := d.Id()[:i], d.Id()[i+1:]
+
+	check, err := resolveCheckImportID(meta.(*providerConfig).withContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsString(check.RecipientIDs, recipientID) {
+		return nil, fmt.Errorf("recipient %s is not attached to check %s", recipientID, check.Token)
+	}
+
+	d.SetId(checkRecipientID(check.Token, recipientID))
+	return []*schema.ResourceData{d}, nil
+}
+
+// statusPageCheckImport imports an updown_status_page_check from `<status_page_token>/<check_token>`
+func statusPageCheckImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseStatusPageCheckID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupClient creates a test HTTP server and a Client configured to talk to it, and a
+// teardown function that must be called when the test is done.
+func setupClient() (mux *http.ServeMux, client *updown.Client, teardown func()) {
+	mux = http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client = updown.NewClient("test-api-key", nil)
+	u, _ := url.Parse(server.URL + "/")
+	client.BaseURL = u
+
+	return mux, client, server.Close
+}
+
+func handleChecks(mux *http.ServeMux) {
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[
+			{"token":"aaaa","type":"https","alias":"Website","url":"https://example.com/"},
+			{"token":"bbbb","type":"https","alias":"Duplicate","url":"https://example.org"},
+			{"token":"cccc","type":"http","alias":"Duplicate","url":"http://example.net"},
+			{"token":"dddd","type":"pulse","alias":"Backup","url":"https://pulse.updown.io/dddd/<redacted>"}
+		]`)
+	})
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"token":"aaaa","type":"https","alias":"Website"}`)
+	})
+}
+
+func TestResolveCheckImportID(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleChecks(mux)
+
+	for id, token := range map[string]string{
+		"aaaa":                                 "aaaa",
+		"alias:Website":                        "aaaa",
+		"url:https://example.com":              "aaaa",
+		"alias:Backup":                         "dddd",
+		"url:https://pulse.updown.io/dddd/key": "dddd",
+	} {
+		check, err := resolveCheckImportID(&providerConfig{client: client}, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, token, check.Token, id)
+	}
+}
+
+func TestResolveCheckImportID_AliasPrefix(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[
+			{"token":"aaaa","type":"https","alias":"[tf] Website","url":"https://example.com/"},
+			{"token":"bbbb","type":"https","alias":"Blog","url":"https://example.org"}
+		]`)
+	})
+	config := &providerConfig{client: client, aliasPrefix: "[tf] "}
+
+	for id, token := range map[string]string{
+		"alias:Website":      "aaaa",
+		"alias:[tf] Website": "aaaa",
+		"alias:Blog":         "bbbb",
+	} {
+		check, err := resolveCheckImportID(config, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, token, check.Token, id)
+	}
+}
+
+func TestResolveCheckImportID_NotFound(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleChecks(mux)
+
+	_, err := resolveCheckImportID(&providerConfig{client: client}, "alias:Unknown")
+	assert.EqualError(t, err, `no check found with alias "Unknown"`)
+
+	_, err = resolveCheckImportID(&providerConfig{client: client}, "zzzz")
+	assert.Error(t, err)
+}
+
+func TestResolveCheckImportID_Ambiguous(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleChecks(mux)
+
+	_, err := resolveCheckImportID(&providerConfig{client: client}, "alias:Duplicate")
+	assert.EqualError(t, err, `2 checks found with alias "Duplicate" (bbbb, cccc), import it by token instead`)
+}
+
+func TestPulseImport(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleChecks(mux)
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"token":"dddd","type":"pulse","alias":"Backup","url":"https://pulse.updown.io/dddd/<redacted>"}`)
+	})
+	meta := &providerConfig{client: client}
+
+	for id, pulseURL := range map[string]string{
+		"dddd": "",
+		"dddd,https://pulse.updown.io/dddd/secret":         "https://pulse.updown.io/dddd/secret",
+		"alias:Backup,https://pulse.updown.io/dddd/secret": "https://pulse.updown.io/dddd/secret",
+		"url:https://pulse.updown.io/dddd/secret":          "https://pulse.updown.io/dddd/secret",
+	} {
+		d := pulseResource().TestResourceData()
+		d.SetId(id)
+
+		res, err := pulseImport(context.Background(), d, meta)
+		require.NoError(t, err, id)
+		require.Len(t, res, 1)
+		assert.Equal(t, "dddd", res[0].Id(), id)
+		assert.Equal(t, pulseURL, res[0].Get("pulse_url"), id)
+	}
+}
+
+func TestPulseImport_Errors(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	handleChecks(mux)
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"token":"dddd","type":"pulse","url":"https://pulse.updown.io/dddd/<redacted>"}`)
+	})
+	meta := &providerConfig{client: client}
+
+	d := pulseResource().TestResourceData()
+	d.SetId("dddd,https://pulse.updown.io/eeee/secret")
+	_, err := pulseImport(context.Background(), d, meta)
+	assert.EqualError(t, err, "pulse URL given on import does not belong to pulse check dddd")
+
+	d.SetId("aaaa")
+	_, err = pulseImport(context.Background(), d, meta)
+	assert.EqualError(t, err, "check aaaa is not a pulse check (type: https), import it as an updown_check resource")
+}
+
+func TestResolveRecipientImportID(t *testing.T) {
+	recipients := []updown.Recipient{
+		{ID: "email:123", Type: updown.RecipientTypeEmail, Value: "ops@example.com"},
+		{ID: "email:456", Type: updown.RecipientTypeEmail, Name: "dev@example.com"},
+		{ID: "webhook:789", Type: updown.RecipientTypeWebhook, Value: "https://example.com/hook"},
+		{ID: "webhook:790", Type: updown.RecipientTypeWebhook, Value: "https://example.com/dup"},
+		{ID: "webhook:791", Type: updown.RecipientTypeWebhook, Value: "https://example.com/dup"},
+	}
+
+	for id, expected := range map[string]string{
+		"email:123":                        "email:123",
+		"email:OPS@example.com":            "email:123",
+		"email:dev@example.com":            "email:456",
+		"webhook:https://example.com/hook": "webhook:789",
+	} {
+		r, err := resolveRecipientImportID(recipients, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, expected, r.ID, id)
+	}
+
+	_, err := resolveRecipientImportID(recipients, "sms:+33600000000")
+	assert.EqualError(t, err, `no sms recipient found with value "+33600000000"`)
+
+	_, err = resolveRecipientImportID(recipients, "webhook:https://example.com/dup")
+	assert.EqualError(t, err, `2 webhook recipients found with value "https://example.com/dup" (webhook:790, webhook:791), import it by ID instead`)
+
+	_, err = resolveRecipientImportID(recipients, "missing")
+	assert.EqualError(t, err, `no recipient found with ID "missing"`)
+}
@@ -0,0 +1,158 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// Keys of the lists kept by listCache
+const (
+	listChecks      = "checks"
+	listRecipients  = "recipients"
+	listStatusPages = "status_pages"
+)
+
+// listCache keeps snapshots of the list endpoints of the API for a limited time, so that
+// refreshing many resources lists them once rather than once per resource. Concurrent loads of
+// the same list share a single request. Any write made through the transport it wraps
+// invalidates every snapshot, as writes to one endpoint can change the others, such as a
+// selected recipient being added to the checks. The writes whose payload is built from a list
+// invalidate the cache first, so that they do not revert the changes made since its snapshot by
+// someone else. A nil *listCache caches nothing.
+type listCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	done     chan struct{}
+	value    interface{}
+	err      error
+	loadedAt time.Time
+}
+
+// newListCache returns a cache keeping snapshots for ttl, or nil when ttl is not positive
+func newListCache(ttl time.Duration) *listCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &listCache{ttl: ttl, now: time.Now, entries: map[string]*listCacheEntry{}}
+}
+
+// get returns the snapshot of the list key, calling load when it is missing or expired. The
+// returned value is shared and must not be modified. Errors are not cached.
+func (c *listCache) get(key string, load func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		select {
+		case <-e.done:
+			if c.now().Sub(e.loadedAt) >= c.ttl {
+				ok = false
+			}
+		default:
+			// Loading, wait for it below
+		}
+	}
+	if !ok {
+		e = &listCacheEntry{done: make(chan struct{})}
+		c.entries[key] = e
+		c.mu.Unlock()
+
+		e.value, e.err = load()
+		e.loadedAt = c.now()
+		close(e.done)
+
+		if e.err != nil {
+			c.mu.Lock()
+			if c.entries[key] == e {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		return e.value, e.err
+	}
+	c.mu.Unlock()
+
+	<-e.done
+	return e.value, e.err
+}
+
+// invalidate drops every snapshot. Loads in progress complete for the callers waiting on them,
+// but are not kept.
+func (c *listCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries = map[string]*listCacheEntry{}
+	c.mu.Unlock()
+}
+
+// transport returns an http.RoundTripper sending requests through base, or the default
+// transport when nil, which invalidates the cache once a write succeeded
+func (c *listCache) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if c == nil {
+		return base
+	}
+	return &invalidatingTransport{base: base, cache: c}
+}
+
+type invalidatingTransport struct {
+	base  http.RoundTripper
+	cache *listCache
+}
+
+func (t *invalidatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		// Also invalidate on failures, the write may have been applied anyway
+		t.cache.invalidate()
+	}
+	return resp, err
+}
+
+// listChecks lists the checks through the list cache
+func (c *providerConfig) listChecks() ([]updown.Check, error) {
+	v, err := c.lists.get(listChecks, func() (interface{}, error) {
+		checks, _, err := c.client.Check.List()
+		return checks, err
+	})
+	checks, _ := v.([]updown.Check)
+	return checks, err
+}
+
+// listRecipients lists the recipients through the list cache
+func (c *providerConfig) listRecipients() ([]updown.Recipient, error) {
+	v, err := c.lists.get(listRecipients, func() (interface{}, error) {
+		recipients, _, err := c.client.Recipient.List()
+		return recipients, err
+	})
+	recipients, _ := v.([]updown.Recipient)
+	return recipients, err
+}
+
+// listStatusPages lists the status pages through the list cache
+func (c *providerConfig) listStatusPages() ([]updown.StatusPage, error) {
+	v, err := c.lists.get(listStatusPages, func() (interface{}, error) {
+		pages, _, err := c.client.StatusPage.List()
+		return pages, err
+	})
+	pages, _ := v.([]updown.StatusPage)
+	return pages, err
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCachedConfig creates a test HTTP server and a provider configuration talking to it
+// through a list cache, and a teardown function that must be called when the test is done.
+func setupCachedConfig(ttl time.Duration) (mux *http.ServeMux, config *providerConfig, teardown func()) {
+	mux = http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	lists := newListCache(ttl)
+	client := updown.NewClient("test-api-key", &http.Client{Transport: lists.transport(nil)})
+	u, _ := url.Parse(server.URL + "/")
+	client.BaseURL = u
+
+	return mux, &providerConfig{client: client, lists: lists}, server.Close
+}
+
+func TestListCache_Refresh(t *testing.T) {
+	mux, config, teardown := setupCachedConfig(time.Minute)
+	defer teardown()
+
+	const n = 300
+	var recipientLists, pageLists int32
+	mux.HandleFunc("/recipients", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&recipientLists, 1)
+			// Let the concurrent reads pile up on the same load
+			time.Sleep(10 * time.Millisecond)
+			fmt.Fprint(w, "[")
+			for i := 0; i < n; i++ {
+				if i > 0 {
+					fmt.Fprint(w, ",")
+				}
+				fmt.Fprintf(w, `{"id":"email:%d","type":"email","value":"user%d@example.com"}`, i, i)
+			}
+			fmt.Fprint(w, "]")
+			return
+		}
+		fmt.Fprint(w, `{"id":"email:new","type":"email","value":"new@example.com"}`)
+	})
+	mux.HandleFunc("/status_pages", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&pageLists, 1)
+		fmt.Fprint(w, `[{"token":"pppp","name":"Company","checks":["aaaa"]}]`)
+	})
+
+	// Refresh N recipients concurrently, as Terraform would
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := schema.TestResourceDataRaw(t, recipientResource().Schema, map[string]interface{}{})
+			d.SetId(fmt.Sprintf("email:%d", i))
+			assert.NoError(t, recipientRead(d, config))
+			assert.Equal(t, fmt.Sprintf("user%d@example.com", i), d.Get("value"))
+		}(i)
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, recipientLists)
+
+	for i := 0; i < n; i++ {
+		d := schema.TestResourceDataRaw(t, statusPageResource().Schema, map[string]interface{}{})
+		d.SetId("pppp")
+		require.NoError(t, statusPageRead(d, config))
+		assert.Equal(t, "Company", d.Get("name"))
+	}
+	assert.EqualValues(t, 1, pageLists)
+
+	// Writes invalidate the snapshots
+	_, _, err := config.client.Recipient.Add(updown.RecipientItem{Type: updown.RecipientTypeEmail, Value: "new@example.com"})
+	require.NoError(t, err)
+	_, err = config.listRecipients()
+	require.NoError(t, err)
+	_, err = config.listStatusPages()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, recipientLists)
+	assert.EqualValues(t, 2, pageLists)
+}
+
+func TestListCache_Expiry(t *testing.T) {
+	cache := newListCache(time.Minute)
+	now := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	var loads int
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+
+	v, err := cache.get(listChecks, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(59 * time.Second)
+	v, _ = cache.get(listChecks, load)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(time.Second)
+	v, _ = cache.get(listChecks, load)
+	assert.Equal(t, 2, v)
+}
+
+func TestListCache_Errors(t *testing.T) {
+	cache := newListCache(time.Minute)
+
+	var loads int
+	_, err := cache.get(listChecks, func() (interface{}, error) {
+		loads++
+		return nil, errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+
+	v, err := cache.get(listChecks, func() (interface{}, error) {
+		loads++
+		return "checks", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "checks", v)
+	assert.Equal(t, 2, loads)
+}
+
+func TestListCache_Disabled(t *testing.T) {
+	cache := newListCache(0)
+	assert.Nil(t, cache)
+
+	var loads int
+	for i := 0; i < 3; i++ {
+		_, err := cache.get(listChecks, func() (interface{}, error) {
+			loads++
+			return nil, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, loads)
+	assert.Equal(t, http.DefaultTransport, cache.transport(nil))
+}
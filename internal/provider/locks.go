@@ -0,0 +1,39 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"sync"
+)
+
+// mutexKV is a set of mutexes identified by a key. It serializes the read-modify-write
+// updates made to the same check by several resources.
+type mutexKV struct {
+	mu    sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{store: map[string]*sync.Mutex{}}
+}
+
+// Lock locks the mutex of key, creating it if needed
+func (m *mutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+// Unlock unlocks the mutex of key
+func (m *mutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
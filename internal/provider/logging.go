@@ -0,0 +1,71 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"context"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// apiLogSubsystem is the tflog subsystem of the requests to the API. Its level can be set
+// apart with the TF_LOG_PROVIDER_UPDOWN_API env variable.
+const apiLogSubsystem = "api"
+
+// apiLogHook returns a client hook logging every request to the API with tflog: its method,
+// URL and status at debug level, its headers and bodies at trace level. The client redacts
+// their secrets, the API key is also masked from every field and message of the subsystem.
+//
+// The requests are logged with their context, which carries the logger of the resource operation
+// sending them, as every operation gives it to the client with updown.Client.WithContext.
+func apiLogHook(apiKey string) updown.Hook {
+	logContext := func(ctx context.Context) context.Context {
+		ctx = tflog.NewSubsystem(ctx, apiLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_UPDOWN", apiLogSubsystem))
+		if apiKey != "" {
+			ctx = tflog.SubsystemMaskLogStrings(ctx, apiLogSubsystem, apiKey)
+		}
+		return ctx
+	}
+
+	return updown.Hook{
+		BeforeRequest: func(ctx context.Context, req updown.RequestInfo) {
+			ctx = logContext(ctx)
+			fields := map[string]interface{}{
+				"method":  req.Method,
+				"url":     req.URL,
+				"attempt": req.Attempt,
+			}
+			tflog.SubsystemDebug(ctx, apiLogSubsystem, "Sending request to the updown.io API", fields)
+			tflog.SubsystemTrace(ctx, apiLogSubsystem, "Request to the updown.io API", map[string]interface{}{
+				"method":  req.Method,
+				"url":     req.URL,
+				"headers": req.Header,
+				"body":    req.Body,
+			})
+		},
+		AfterResponse: func(ctx context.Context, resp updown.ResponseInfo) {
+			ctx = logContext(ctx)
+			fields := map[string]interface{}{
+				"method":      resp.Request.Method,
+				"url":         resp.Request.URL,
+				"attempt":     resp.Request.Attempt,
+				"duration_ms": resp.Duration.Milliseconds(),
+			}
+			if resp.Err != nil {
+				fields["error"] = resp.Err.Error()
+				tflog.SubsystemDebug(ctx, apiLogSubsystem, "Request to the updown.io API failed", fields)
+				return
+			}
+
+			fields["status"] = resp.StatusCode
+			tflog.SubsystemDebug(ctx, apiLogSubsystem, "Received response from the updown.io API", fields)
+			tflog.SubsystemTrace(ctx, apiLogSubsystem, "Response from the updown.io API", map[string]interface{}{
+				"method":  resp.Request.Method,
+				"url":     resp.Request.URL,
+				"status":  resp.StatusCode,
+				"headers": resp.Header,
+				"body":    resp.Body,
+			})
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPILogHook(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"dddd","url":"https://pulse.updown.io/dddd/secret","message":"key test-api-key"}`))
+	})
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	client.APIKey = "test-api-key"
+	client.Hooks = []updown.Hook{apiLogHook(client.APIKey)}
+
+	_, _, err := client.WithContext(ctx).Check.Update("dddd", updown.CheckItem{
+		CustomHeaders: map[string]string{"X-Auth-Token": "header-token"},
+	})
+	require.NoError(t, err)
+
+	logged := output.String()
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+
+	messages := []string{}
+	for _, e := range entries {
+		messages = append(messages, e["@message"].(string))
+		assert.Equal(t, "provider."+apiLogSubsystem, e["@module"])
+		assert.Equal(t, "PUT", e["method"])
+	}
+	assert.Equal(t, []string{
+		"Sending request to the updown.io API",
+		"Request to the updown.io API",
+		"Received response from the updown.io API",
+		"Response from the updown.io API",
+	}, messages)
+	assert.EqualValues(t, 200, entries[2]["status"])
+
+	for _, secret := range []string{"test-api-key", "header-token", "/dddd/secret"} {
+		assert.NotContains(t, logged, secret)
+	}
+	assert.Contains(t, logged, "https://pulse.updown.io/dddd/\\u003credacted\\u003e")
+}
+
+func TestAPILogHook_ResourceContext(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"token":"aaaa","type":"http","url":"https://example.com"}`))
+	})
+	client.Hooks = []updown.Hook{apiLogHook(client.APIKey)}
+
+	// The requests of an operation are logged with its context
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	d := schema.TestResourceDataRaw(t, checkResource().Schema, map[string]interface{}{})
+	d.SetId("aaaa")
+	require.Empty(t, checkResource().ReadContext(ctx, d, &providerConfig{client: client}))
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "provider."+apiLogSubsystem, entries[0]["@module"])
+	assert.Equal(t, "GET", entries[0]["method"])
+}
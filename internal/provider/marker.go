@@ -0,0 +1,48 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// markedSchema is the attribute telling if a check carries the provider alias_prefix
+func markedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the alias of the check carries the provider `alias_prefix`, which is added on the next apply otherwise.",
+	}
+}
+
+// aliasPrefixFrom returns the provider alias_prefix, meta can be nil when the provider is not
+// configured yet
+func aliasPrefixFrom(meta interface{}) string {
+	if c, ok := meta.(*providerConfig); ok {
+		return c.aliasPrefix
+	}
+	return ""
+}
+
+// unmarkAlias returns the alias of a check without the provider prefix, and whether it
+// carried it
+func unmarkAlias(prefix, alias string) (string, bool) {
+	if prefix == "" || !strings.HasPrefix(alias, prefix) {
+		return alias, false
+	}
+	return strings.TrimPrefix(alias, prefix), true
+}
+
+// aliasPrefixCustomizeDiff plans an update of the checks which do not carry the provider
+// alias_prefix yet, such as imported ones, so that it gets added
+func aliasPrefixCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if aliasPrefixFrom(meta) == "" {
+		return nil
+	}
+	if d.Id() == "" || !d.Get("marked").(bool) {
+		return d.SetNew("marked", true)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarkAlias(t *testing.T) {
+	for _, tc := range []struct {
+		prefix, alias, expected string
+		marked                  bool
+	}{
+		{"[tf] ", "[tf] Website", "Website", true},
+		{"[tf] ", "[tf] ", "", true},
+		{"[tf] ", "Website", "Website", false},
+		{"", "[tf] Website", "[tf] Website", false},
+	} {
+		alias, marked := unmarkAlias(tc.prefix, tc.alias)
+		assert.Equal(t, tc.expected, alias, tc.alias)
+		assert.Equal(t, tc.marked, marked, tc.alias)
+	}
+}
@@ -0,0 +1,108 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// muteUntilLayouts are the timestamp formats accepted by the API or returned by it
+var muteUntilLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// normalizeURL lowercases the scheme and host of a URL and strips the trailing slash of its
+// path, which the API adds or removes. Values which are not URLs are returned unchanged.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+	return u.String()
+}
+
+// normalizeMuteUntil returns timestamps in RFC 3339 UTC form, and keywords such as 'recovery'
+// or 'forever' in lower case. Values it cannot parse are returned unchanged.
+func normalizeMuteUntil(raw string) string {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range muteUntilLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return strings.ToLower(raw)
+}
+
+// normalizeHeaders returns the headers with their names in canonical form, header names being
+// case insensitive.
+func normalizeHeaders(headers map[string]interface{}) map[string]string {
+	res := make(map[string]string, len(headers))
+	for k, v := range headers {
+		s, _ := v.(string)
+		res[http.CanonicalHeaderKey(k)] = s
+	}
+	return res
+}
+
+// floatsEqual compares two floats ignoring representation noise
+func floatsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func suppressEquivalentURL(_, old, new string, _ *schema.ResourceData) bool {
+	return normalizeURL(old) == normalizeURL(new)
+}
+
+func suppressEquivalentMuteUntil(_, old, new string, _ *schema.ResourceData) bool {
+	return normalizeMuteUntil(old) == normalizeMuteUntil(new)
+}
+
+func suppressEquivalentFloat(_, old, new string, _ *schema.ResourceData) bool {
+	a, errA := strconv.ParseFloat(old, 64)
+	b, errB := strconv.ParseFloat(new, 64)
+	if errA != nil || errB != nil {
+		return old == new
+	}
+	return floatsEqual(a, b)
+}
+
+// suppressEquivalentHeaders is called for every element of the custom_headers map, it
+// suppresses the whole diff when both maps only differ by the case of the header names.
+func suppressEquivalentHeaders(_, _, _ string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("custom_headers")
+	oldHeaders, _ := o.(map[string]interface{})
+	newHeaders, _ := n.(map[string]interface{})
+	return equivalentHeaders(oldHeaders, newHeaders)
+}
+
+// equivalentHeaders tells if two sets of headers only differ by the case of their names
+func equivalentHeaders(a, b map[string]interface{}) bool {
+	na, nb := normalizeHeaders(a), normalizeHeaders(b)
+	if len(na) != len(nb) {
+		return false
+	}
+	for k, v := range na {
+		if w, ok := nb[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	for raw, expected := range map[string]string{
+		"https://example.com":            "https://example.com",
+		"https://example.com/":           "https://example.com",
+		"HTTPS://Example.COM/healthz/":   "https://example.com/healthz",
+		"https://example.com/a/b?q=1":    "https://example.com/a/b?q=1",
+		"tcp://db.example.com:5432":      "tcp://db.example.com:5432",
+		"tcps://DB.example.com:5432/":    "tcps://db.example.com:5432",
+		"not a url":                      "not a url",
+		"https://example.com/Case/Path/": "https://example.com/Case/Path",
+	} {
+		assert.Equal(t, expected, normalizeURL(raw), raw)
+	}
+}
+
+func TestSuppressEquivalentURL(t *testing.T) {
+	assert.True(t, suppressEquivalentURL("url", "https://example.com/", "https://example.com", nil))
+	assert.True(t, suppressEquivalentURL("url", "https://example.com/path/", "https://EXAMPLE.com/path", nil))
+	assert.False(t, suppressEquivalentURL("url", "https://example.com/a", "https://example.com/b", nil))
+	assert.False(t, suppressEquivalentURL("url", "http://example.com", "https://example.com", nil))
+}
+
+func TestNormalizeMuteUntil(t *testing.T) {
+	for raw, expected := range map[string]string{
+		"2030-01-01T10:00:00Z":          "2030-01-01T10:00:00Z",
+		"2030-01-01T10:00:00.000Z":      "2030-01-01T10:00:00Z",
+		"2030-01-01T12:00:00+02:00":     "2030-01-01T10:00:00Z",
+		"2030-01-01T12:00:00+0200":      "2030-01-01T10:00:00Z",
+		"2030-01-01 10:00:00 UTC":       "2030-01-01T10:00:00Z",
+		"2030-01-01 12:00:00 +0200":     "2030-01-01T10:00:00Z",
+		"2030-01-01 10:00:00":           "2030-01-01T10:00:00Z",
+		"2030-01-01":                    "2030-01-01T00:00:00Z",
+		"recovery":                      "recovery",
+		"Forever":                       "forever",
+		"tomorrow":                      "tomorrow",
+		" 2030-01-01T10:00:00.123456Z ": "2030-01-01T10:00:00Z",
+	} {
+		assert.Equal(t, expected, normalizeMuteUntil(raw), raw)
+	}
+}
+
+func TestSuppressEquivalentMuteUntil(t *testing.T) {
+	assert.True(t, suppressEquivalentMuteUntil("mute_until", "2030-01-01 10:00:00 UTC", "2030-01-01T10:00:00Z", nil))
+	assert.True(t, suppressEquivalentMuteUntil("mute_until", "forever", "forever", nil))
+	assert.False(t, suppressEquivalentMuteUntil("mute_until", "2030-01-01T10:00:00Z", "2030-01-01T11:00:00Z", nil))
+	assert.False(t, suppressEquivalentMuteUntil("mute_until", "recovery", "", nil))
+}
+
+func TestSuppressEquivalentFloat(t *testing.T) {
+	assert.True(t, suppressEquivalentFloat("apdex_t", "1", "1.0", nil))
+	assert.True(t, suppressEquivalentFloat("apdex_t", "0.125", "0.12500000000001", nil))
+	assert.False(t, suppressEquivalentFloat("apdex_t", "0.25", "0.5", nil))
+	assert.False(t, suppressEquivalentFloat("apdex_t", "", "0.5", nil))
+}
+
+func TestEquivalentHeaders(t *testing.T) {
+	assert.True(t, equivalentHeaders(
+		map[string]interface{}{"x-api-token": "secret", "accept": "text/html"},
+		map[string]interface{}{"X-Api-Token": "secret", "ACCEPT": "text/html"},
+	))
+	assert.True(t, equivalentHeaders(nil, map[string]interface{}{}))
+	assert.False(t, equivalentHeaders(
+		map[string]interface{}{"x-api-token": "secret"},
+		map[string]interface{}{"X-Api-Token": "other"},
+	))
+	assert.False(t, equivalentHeaders(
+		map[string]interface{}{"x-api-token": "secret"},
+		map[string]interface{}{"X-Api-Token": "secret", "Accept": "text/html"},
+	))
+}
@@ -2,8 +2,20 @@
 package provider
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // New returns a Terraform provider resource
@@ -17,24 +29,205 @@ func New() func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("UPDOWN_API_KEY", ""),
 					Description: "API key to use in order to authenticated against updown.io API.",
 				},
+				"base_url": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_BASE_URL", "https://updown.io/api/"),
+					Description:  "Base URL of the updown.io API, useful to target a mock server. Can also be set using the UPDOWN_BASE_URL env variable.",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"request_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_REQUEST_TIMEOUT", 30),
+					Description:  "Timeout in seconds of each request made to the API, 0 disables it. Can also be set using the UPDOWN_REQUEST_TIMEOUT env variable.",
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_MAX_RETRIES", 3),
+					Description:  "Maximum number of retries of rate limited requests, and of failed idempotent requests. Can also be set using the UPDOWN_MAX_RETRIES env variable.",
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"list_cache_ttl": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_LIST_CACHE_TTL", 30),
+					Description:  "Duration in seconds for which the lists of checks, recipients and status pages are reused between resources, rather than listed again for each of them. Any change made by the provider clears them, 0 disables it. Can also be set using the UPDOWN_LIST_CACHE_TTL env variable.",
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"rate_limit": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_RATE_LIMIT", 0),
+					Description:  "Maximum number of requests per second sent to the API, 0 means unlimited. Can also be set using the UPDOWN_RATE_LIMIT env variable.",
+					ValidateFunc: validation.FloatAtLeast(0),
+				},
+				"http_proxy": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("UPDOWN_HTTP_PROXY", ""),
+					Description:  "URL of the proxy to send requests through. Defaults to the standard HTTPS_PROXY/NO_PROXY env variables. Can also be set using the UPDOWN_HTTP_PROXY env variable.",
+					ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				},
+				"ca_cert_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("UPDOWN_CA_CERT_FILE", ""),
+					Description: "Path to a PEM encoded CA bundle trusted in addition to the system ones, useful behind TLS intercepting proxies. Can also be set using the UPDOWN_CA_CERT_FILE env variable.",
+				},
+				"user_agent_suffix": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("UPDOWN_USER_AGENT_SUFFIX", ""),
+					Description: "Extra string appended to the User-Agent header of every request. Can also be set using the UPDOWN_USER_AGENT_SUFFIX env variable.",
+				},
+				"allow_pulse_url_recovery": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("UPDOWN_ALLOW_PULSE_URL_RECOVERY", false),
+					Description: "Allow the provider to briefly toggle the enabled flag of a pulse check to recover its redacted URL, when it is not known from the state or the import ID. Can also be set using the UPDOWN_ALLOW_PULSE_URL_RECOVERY env variable.",
+				},
+				"alias_prefix": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("UPDOWN_ALIAS_PREFIX", ""),
+					Description: "Prefix added to the alias of every check and pulse managed by the provider, such as '[tf] ', to tell them apart with the `updown_unmanaged_checks` data source. It is not part of the `alias` attributes. Can also be set using the UPDOWN_ALIAS_PREFIX env variable.",
+				},
+				"defaults": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Default values applied to every `updown_check` and `updown_pulse` which does not set them.",
+					Elem:        defaultsResource(),
+				},
 			},
 
-			ConfigureFunc: providerConfigure,
+			ConfigureContextFunc: func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+				config, err := providerConfigure(ctx, d)
+				if err != nil {
+					return nil, diag.FromErr(err)
+				}
+				return config, nil
+			},
 
 			DataSourcesMap: map[string]*schema.Resource{
-				"updown_nodes": nodesDataSource(),
+				"updown_nodes":            nodesDataSource(),
+				"updown_recipient":        recipientDataSource(),
+				"updown_recipients":       recipientsDataSource(),
+				"updown_unmanaged_checks": unmanagedChecksDataSource(),
 			},
 
 			ResourcesMap: map[string]*schema.Resource{
-				"updown_check":       checkResource(),
-				"updown_pulse":       pulseResource(),
-				"updown_recipient":   recipientResource(),
-				"updown_status_page": statusPageResource(),
+				"updown_check":              checkResource(),
+				"updown_check_recipient":    checkRecipientResource(),
+				"updown_maintenance_window": maintenanceWindowResource(),
+				"updown_pulse":              pulseResource(),
+				"updown_recipient":          recipientResource(),
+				"updown_status_page":        statusPageResource(),
+				"updown_status_page_check":  statusPageCheckResource(),
 			},
 		}
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	return updown.NewClient(d.Get("api_key").(string), nil), nil
+// providerConfig is the configured provider, passed as meta to every resource
+type providerConfig struct {
+	client                *updown.Client
+	defaults              resourceDefaults
+	allowPulseURLRecovery bool
+	checkLocks            *mutexKV
+	statusPageLocks       *mutexKV
+	aliasPrefix           string
+	lists                 *listCache
+}
+
+// withContext returns a copy of the config whose client sends its requests with ctx, the context
+// of a resource operation, so that they are cancelled with it and logged with its logger
+func (c *providerConfig) withContext(ctx context.Context) *providerConfig {
+	copied := *c
+	copied.client = c.client.WithContext(ctx)
+	return &copied
+}
+
+// withRequestContext adapts the CRUD functions which do not take a context to the context aware
+// ones of the SDK, giving them the config of withContext as meta
+func withRequestContext(f func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		return diag.FromErr(f(d, meta.(*providerConfig).withContext(ctx)))
+	}
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (*providerConfig, error) {
+	httpClient, err := newHTTPClient(d)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := newListCache(time.Duration(d.Get("list_cache_ttl").(int)) * time.Second)
+	httpClient.Transport = lists.transport(httpClient.Transport)
+
+	client := updown.NewClient(d.Get("api_key").(string), httpClient)
+
+	baseURL := d.Get("base_url").(string)
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	if client.BaseURL, err = url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("parsing base_url: %w", err)
+	}
+
+	if suffix := d.Get("user_agent_suffix").(string); suffix != "" {
+		client.UserAgent += " " + suffix
+	}
+
+	client.MaxRetries = d.Get("max_retries").(int)
+	client.RateLimiter = updown.NewRateLimiter(d.Get("rate_limit").(float64))
+	client.Hooks = append(client.Hooks, apiLogHook(client.APIKey))
+
+	return &providerConfig{
+		client:                client,
+		defaults:              expandResourceDefaults(d.Get("defaults").([]interface{})),
+		allowPulseURLRecovery: d.Get("allow_pulse_url_recovery").(bool),
+		checkLocks:            newMutexKV(),
+		statusPageLocks:       newMutexKV(),
+		aliasPrefix:           d.Get("alias_prefix").(string),
+		lists:                 lists,
+	}, nil
+}
+
+// newHTTPClient builds the HTTP client used to talk to the API from the timeout, proxy and
+// TLS settings of the provider.
+func newHTTPClient(d *schema.ResourceData) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if v := d.Get("http_proxy").(string); v != "" {
+		proxyURL, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing http_proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if v := d.Get("ca_cert_file").(string); v != "" {
+		pem, err := os.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_cert_file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert_file %s does not contain any PEM encoded certificate", v)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(d.Get("request_timeout").(int)) * time.Second,
+	}, nil
 }
@@ -2,10 +2,14 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -13,21 +17,27 @@ func checkResource() *schema.Resource {
 	return &schema.Resource{
 		Description: "`updown_check` defines a check",
 
-		Create: checkCreate,
-		Read:   checkRead,
-		Delete: checkDelete,
-		Update: checkUpdate,
-		Exists: checkExists,
+		CreateContext: checkCreate,
+		ReadContext:   withRequestContext(checkRead),
+		DeleteContext: withRequestContext(checkDelete),
+		UpdateContext: withRequestContext(checkUpdate),
+
+		CustomizeDiff: customdiff.All(checkCustomizeDiff, aliasPrefixCustomizeDiff),
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: checkImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: withDestroySchema(map[string]*schema.Schema{
 			"url": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The URL you want to monitor.",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The URL you want to monitor.",
+				DiffSuppressFunc: suppressEquivalentURL,
 			},
 			"period": {
 				Type:        schema.TypeInt,
@@ -36,10 +46,11 @@ func checkResource() *schema.Resource {
 				Default:     60,
 			},
 			"apdex_t": {
-				Type:        schema.TypeFloat,
-				Optional:    true,
-				Description: "APDEX threshold in seconds (0.125, 0.25, 0.5, 1.0 or 2.0).",
-				Default:     0.5,
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				Computed:         true,
+				Description:      "APDEX threshold in seconds (0.125, 0.25, 0.5, 1.0 or 2.0). Defaults to the provider `defaults`, or 0.5.",
+				DiffSuppressFunc: suppressEquivalentFloat,
 			},
 			"enabled": {
 				Type:        schema.TypeBool,
@@ -64,14 +75,16 @@ func checkResource() *schema.Resource {
 				Description: "Search for this string in the page.",
 			},
 			"mute_until": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Mute notifications until given time, accepts a time, 'recovery' or 'forever'.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Mute notifications until given time, accepts a time, 'recovery' or 'forever'.",
+				DiffSuppressFunc: suppressEquivalentMuteUntil,
 			},
 			"disabled_locations": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Disabled monitoring locations. It's a lsit of abbreviated location names.",
+				Computed:    true,
+				Description: "Disabled monitoring locations. It's a lsit of abbreviated location names. Defaults to the provider `defaults`.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -79,11 +92,18 @@ func checkResource() *schema.Resource {
 			"recipients": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Selected alert recipients. It's an array of recipient IDs you can get from the recipients API.",
+				Computed:    true,
+				Description: "Selected alert recipients. It's an array of recipient IDs you can get from the recipients API or the `updown_recipients` data source, including the integrations set up from the web UI. Defaults to the provider `defaults`.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"ignore_external_recipients": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave alone the recipients attached to the check outside of this resource, such as with `updown_check_recipient`, rather than removing them. Enable it before attaching such recipients, as the ones already read into `recipients` are considered managed by the resource.",
+			},
 			"type": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -91,14 +111,70 @@ func checkResource() *schema.Resource {
 				Description: "Type of check (http, https, icmp, tcp, tcps). Auto-detected from URL scheme if not set.",
 			},
 			"custom_headers": {
-				Type:        schema.TypeMap,
-				Optional:    true,
-				Description: "The HTTP headers you want in requests.",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The HTTP headers you want in requests, merged with the provider `defaults`.",
+				DiffSuppressFunc: suppressEquivalentHeaders,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
-		},
+			"wait_for_first_result": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait on creation until the check has run once, within the `create` timeout.",
+			},
+			"fail_if_down": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				Description:  "Fail the creation, leaving the check tainted, when its first result is down. Requires `wait_for_first_result`.",
+				RequiredWith: []string{"wait_for_first_result"},
+			},
+			"marked": markedSchema(),
+			"down": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the check is down.",
+			},
+			"last_status": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "HTTP status of the last check.",
+			},
+			"last_check_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time of the last check, empty until the check has run.",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error of the last check.",
+			},
+			"uptime": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Uptime percentage of the check over the last 30 days.",
+			},
+			"ssl_valid": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the SSL certificate of the URL is valid.",
+			},
+			"ssl_error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error of the SSL certificate of the URL.",
+			},
+			"ssl_tested_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time of the last test of the SSL certificate.",
+			},
+		}),
 	}
 }
 
@@ -174,27 +250,130 @@ func constructCheckPayload(d *schema.ResourceData) updown.CheckItem {
 	return payload
 }
 
-func checkCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+// checkItemFromCheck returns the payload updating a check with its current values
+func checkItemFromCheck(check updown.Check) updown.CheckItem {
+	payload := updown.CheckItem{
+		Type:              check.Type,
+		Period:            check.Period,
+		Apdex:             check.Apdex,
+		Enabled:           check.Enabled,
+		Published:         check.Published,
+		Alias:             check.Alias,
+		StringMatch:       check.StringMatch,
+		MuteUntil:         check.MuteUntil,
+		DisabledLocations: check.DisabledLocations,
+		RecipientIDs:      check.RecipientIDs,
+		CustomHeaders:     check.CustomHeaders,
+	}
+	// The URL of pulse checks is generated by the API
+	if check.Type != "pulse" {
+		payload.URL = check.URL
+	}
+	return payload
+}
+
+// mergeExternalRecipients returns the recipients to send when updating a check from old to
+// recipients, keeping the current ones which were not managed by the resource
+func mergeExternalRecipients(current, old, recipients []string) []string {
+	merged := append([]string{}, recipients...)
+	for _, id := range current {
+		if !containsString(old, id) && !containsString(merged, id) {
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+// managedRecipients returns the current recipients of a check which are managed by the resource
+func managedRecipients(current, managed []string) []string {
+	recipients := []string{}
+	for _, id := range current {
+		if containsString(managed, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients
+}
+
+func checkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*providerConfig).withContext(ctx)
+	client := config.client
+
+	payload := constructCheckPayload(d)
+	payload.Alias = config.aliasPrefix + payload.Alias
 
-	check, _, err := client.Check.Add(constructCheckPayload(d))
+	check, _, err := client.Check.Add(payload)
 	if err != nil {
-		return fmt.Errorf("creating check with the API: %w", err)
+		return diag.Errorf("creating check with the API: %s", err.Error())
 	}
 
 	d.SetId(check.Token)
 
-	return checkRead(d, meta)
+	if d.Get("wait_for_first_result").(bool) {
+		check, err = waitForFirstResult(ctx, client, check.Token, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if check.Down && d.Get("fail_if_down").(bool) {
+			if err := checkRead(d, config); err != nil {
+				return diag.FromErr(err)
+			}
+			return diag.Errorf("check %s is down after its first run: %s", check.Token, checkFailure(check))
+		}
+	}
+
+	return diag.FromErr(checkRead(d, config))
+}
+
+// Interval between the polls of a check waiting for its first result
+var checkResultPollInterval = 5 * time.Second
+
+// waitForFirstResult polls a check until it has run once, or ctx is done
+func waitForFirstResult(ctx context.Context, client *updown.Client, token string, timeout time.Duration) (updown.Check, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		check, _, err := client.Check.Get(token)
+		if err != nil {
+			return updown.Check{}, fmt.Errorf("reading check from the API: %w", err)
+		}
+		if check.LastCheckAt != "" {
+			return check, nil
+		}
+		if time.Now().Add(checkResultPollInterval).After(deadline) {
+			return updown.Check{}, fmt.Errorf("check %s has not run after %s", token, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return updown.Check{}, fmt.Errorf("waiting for the first result of check %s: %w", token, ctx.Err())
+		case <-time.After(checkResultPollInterval):
+		}
+	}
+}
+
+// checkFailure describes why a check is down
+func checkFailure(check updown.Check) string {
+	if check.Error != "" {
+		return check.Error
+	}
+	return fmt.Sprintf("HTTP status %d", check.LastStatus)
 }
 
 func checkRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	check, _, err := client.Check.Get(d.Id())
+	config := meta.(*providerConfig)
+	check, _, err := config.client.Check.Get(d.Id())
 
 	if err != nil {
 		return fmt.Errorf("reading check from the API: %w", err)
 	}
 
+	alias, marked := unmarkAlias(config.aliasPrefix, check.Alias)
+
+	recipients := check.RecipientIDs
+	if d.Get("ignore_external_recipients").(bool) {
+		recipients = managedRecipients(recipients, setToStringSlice(d.Get("recipients").(*schema.Set)))
+	}
+
 	for k, v := range map[string]interface{}{
 		"url":                check.URL,
 		"type":               check.Type,
@@ -202,12 +381,21 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 		"apdex_t":            check.Apdex,
 		"enabled":            check.Enabled,
 		"published":          check.Published,
-		"alias":              check.Alias,
+		"alias":              alias,
+		"marked":             marked,
 		"string_match":       check.StringMatch,
 		"mute_until":         check.MuteUntil,
 		"disabled_locations": check.DisabledLocations,
-		"recipients":         check.RecipientIDs,
+		"recipients":         recipients,
 		"custom_headers":     check.CustomHeaders,
+		"down":               check.Down,
+		"last_status":        check.LastStatus,
+		"last_check_at":      check.LastCheckAt,
+		"error":              check.Error,
+		"uptime":             check.Uptime,
+		"ssl_valid":          check.SSL.Valid,
+		"ssl_error":          check.SSL.Error,
+		"ssl_tested_at":      check.SSL.TestedAt,
 	} {
 		if err := d.Set(k, v); err != nil {
 			return err
@@ -218,32 +406,35 @@ func checkRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func checkUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	config := meta.(*providerConfig)
 
-	_, _, err := client.Check.Update(d.Id(), constructCheckPayload(d))
-	if err != nil {
-		return fmt.Errorf("updating check with the API: %w", err)
-	}
+	config.checkLocks.Lock(d.Id())
+	defer config.checkLocks.Unlock(d.Id())
 
-	return checkRead(d, meta)
-}
-
-func checkDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	checkDeleted, _, err := client.Check.Remove(d.Id())
+	payload := constructCheckPayload(d)
+	payload.Alias = config.aliasPrefix + payload.Alias
+	if d.HasChange("recipients") {
+		// Send an empty list rather than none when every recipient is removed
+		payload.RecipientIDs = setToStringSlice(d.Get("recipients").(*schema.Set))
+	}
 
-	if err != nil {
-		return fmt.Errorf("removing check from the API: %w", err)
+	if d.Get("ignore_external_recipients").(bool) {
+		check, _, err := config.client.Check.Get(d.Id())
+		if err != nil {
+			return fmt.Errorf("reading check from the API: %w", err)
+		}
+		old, _ := d.GetChange("recipients")
+		payload.RecipientIDs = mergeExternalRecipients(check.RecipientIDs, setToStringSlice(old.(*schema.Set)), payload.RecipientIDs)
 	}
 
-	if !checkDeleted {
-		return fmt.Errorf("check couldn't be deleted")
+	_, _, err := config.client.Check.Update(d.Id(), payload)
+	if err != nil {
+		return fmt.Errorf("updating check with the API: %w", err)
 	}
 
-	return nil
+	return checkRead(d, meta)
 }
 
-func checkExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	err := checkRead(d, meta)
-	return err == nil, err
+func checkDelete(d *schema.ResourceData, meta interface{}) error {
+	return destroyCheck(d, meta, "check")
 }
@@ -0,0 +1,161 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func checkRecipientResource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_check_recipient` attaches a recipient to a check or a pulse, independently of the resource managing the check. " +
+			"Set `ignore_external_recipients` on the `updown_check` or `updown_pulse` so that it does not remove the recipients attached this way.",
+
+		CreateContext: withRequestContext(checkRecipientCreate),
+		ReadContext:   withRequestContext(checkRecipientRead),
+		DeleteContext: withRequestContext(checkRecipientDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: checkRecipientImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"check_token": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Token of the check or pulse.",
+			},
+			"recipient_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the recipient to alert, from an `updown_recipient` resource or the `updown_recipients` data source.",
+			},
+		},
+	}
+}
+
+// checkRecipientID returns the ID of the association between a check and a recipient
+func checkRecipientID(token, recipientID string) string {
+	return token + "/" + recipientID
+}
+
+// parseCheckRecipientID splits the ID of the association between a check and a recipient
+func parseCheckRecipientID(id string) (token, recipientID string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <check_token>/<recipient_id>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func checkRecipientCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*providerConfig)
+	token, recipientID := d.Get("check_token").(string), d.Get("recipient_id").(string)
+
+	config.checkLocks.Lock(token)
+	defer config.checkLocks.Unlock(token)
+
+	check, _, err := config.client.Check.Get(token)
+	if err != nil {
+		return fmt.Errorf("reading check from the API: %w", err)
+	}
+
+	if !containsString(check.RecipientIDs, recipientID) {
+		payload := checkItemFromCheck(check)
+		payload.RecipientIDs = append(append([]string{}, check.RecipientIDs...), recipientID)
+		if _, _, err := config.client.Check.Update(token, payload); err != nil {
+			return fmt.Errorf("adding recipient to the check with the API: %w", err)
+		}
+	}
+
+	d.SetId(checkRecipientID(token, recipientID))
+
+	return checkRecipientRead(d, meta)
+}
+
+func checkRecipientRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerConfig).client
+
+	token, recipientID, err := parseCheckRecipientID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	check, _, err := client.Check.Get(token)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading check from the API: %w", err)
+	}
+
+	if !containsString(check.RecipientIDs, recipientID) {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("check_token", token); err != nil {
+		return err
+	}
+	return d.Set("recipient_id", recipientID)
+}
+
+func checkRecipientDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*providerConfig)
+
+	token, recipientID, err := parseCheckRecipientID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	config.checkLocks.Lock(token)
+	defer config.checkLocks.Unlock(token)
+
+	check, _, err := config.client.Check.Get(token)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading check from the API: %w", err)
+	}
+
+	if !containsString(check.RecipientIDs, recipientID) {
+		return nil
+	}
+
+	payload := checkItemFromCheck(check)
+	payload.RecipientIDs = []string{}
+	for _, id := range check.RecipientIDs {
+		if id != recipientID {
+			payload.RecipientIDs = append(payload.RecipientIDs, id)
+		}
+	}
+	if _, _, err := config.client.Check.Update(token, payload); err != nil {
+		return fmt.Errorf("removing recipient from the check with the API: %w", err)
+	}
+
+	return nil
+}
+
+// isNotFound tells if err is an API error caused by a missing object
+func isNotFound(err error) bool {
+	var errResp *updown.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+func containsString(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handleCheckRecipients serves a check whose recipients can be updated, and returns a function
+// giving its current recipients
+func handleCheckRecipients(t *testing.T, mux *http.ServeMux, recipients []string) func() []string {
+	var mu sync.Mutex
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "PUT" {
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			assert.Equal(t, "https://example.com", item["url"])
+			recipients = []string{}
+			for _, id := range item["recipients"].([]interface{}) {
+				recipients = append(recipients, id.(string))
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(updown.Check{
+			Token: "aaaa", Type: "https", URL: "https://example.com", RecipientIDs: recipients,
+		}))
+	})
+	mux.HandleFunc("/checks/zzzz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not found"}`))
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return recipients
+	}
+}
+
+func TestCheckRecipient(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	recipients := handleCheckRecipients(t, mux, []string{"email:1"})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	// Attach two recipients concurrently, as Terraform would
+	var wg sync.WaitGroup
+	for _, id := range []string{"email:2", "sms:3"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			d := schema.TestResourceDataRaw(t, checkRecipientResource().Schema, map[string]interface{}{
+				"check_token":  "aaaa",
+				"recipient_id": id,
+			})
+			require.NoError(t, checkRecipientCreate(d, meta))
+			assert.Equal(t, "aaaa/"+id, d.Id())
+		}(id)
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []string{"email:1", "email:2", "sms:3"}, recipients())
+
+	d := schema.TestResourceDataRaw(t, checkRecipientResource().Schema, map[string]interface{}{})
+	d.SetId("aaaa/email:2")
+	require.NoError(t, checkRecipientRead(d, meta))
+	assert.Equal(t, "aaaa", d.Get("check_token"))
+	assert.Equal(t, "email:2", d.Get("recipient_id"))
+
+	require.NoError(t, checkRecipientDelete(d, meta))
+	assert.ElementsMatch(t, []string{"email:1", "sms:3"}, recipients())
+
+	// Detached recipients and deleted checks are removed from the state
+	require.NoError(t, checkRecipientRead(d, meta))
+	assert.Empty(t, d.Id())
+
+	d.SetId("zzzz/email:1")
+	require.NoError(t, checkRecipientRead(d, meta))
+	assert.Empty(t, d.Id())
+}
+
+func TestCheckRecipientDelete_LastRecipient(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	recipients := handleCheckRecipients(t, mux, []string{"email:1"})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	d := schema.TestResourceDataRaw(t, checkRecipientResource().Schema, map[string]interface{}{})
+	d.SetId("aaaa/email:1")
+	require.NoError(t, checkRecipientDelete(d, meta))
+	assert.Empty(t, recipients())
+}
+
+func TestParseCheckRecipientID(t *testing.T) {
+	token, recipientID, err := parseCheckRecipientID("aaaa/email:1")
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa", token)
+	assert.Equal(t, "email:1", recipientID)
+
+	for _, id := range []string{"aaaa", "aaaa/", "/email:1"} {
+		_, _, err := parseCheckRecipientID(id)
+		assert.Error(t, err, id)
+	}
+}
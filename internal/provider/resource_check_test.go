@@ -1,3 +1,97 @@
 package provider
 
-// TODO: implement..
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckItemFromCheck(t *testing.T) {
+	check := updown.Check{Token: "aaaa", Type: "https", URL: "https://example.com", Period: 60, Enabled: true, RecipientIDs: []string{"email:1"}}
+	payload := checkItemFromCheck(check)
+	assert.Equal(t, "https://example.com", payload.URL)
+	assert.Equal(t, 60, payload.Period)
+	assert.True(t, payload.Enabled)
+	assert.Equal(t, []string{"email:1"}, payload.RecipientIDs)
+
+	pulse := updown.Check{Token: "dddd", Type: "pulse", URL: "https://pulse.updown.io/dddd/<redacted>"}
+	assert.Empty(t, checkItemFromCheck(pulse).URL)
+}
+
+func TestMergeExternalRecipients(t *testing.T) {
+	// email:3 was attached outside of the resource, which replaces email:2 with email:4
+	assert.Equal(t,
+		[]string{"email:1", "email:4", "email:3"},
+		mergeExternalRecipients([]string{"email:1", "email:2", "email:3"}, []string{"email:1", "email:2"}, []string{"email:1", "email:4"}),
+	)
+	assert.Equal(t, []string{"email:3"}, mergeExternalRecipients([]string{"email:1", "email:3"}, []string{"email:1"}, nil))
+	assert.Equal(t, []string{}, mergeExternalRecipients(nil, []string{"email:1"}, nil))
+}
+
+func TestManagedRecipients(t *testing.T) {
+	assert.Equal(t, []string{"email:1"}, managedRecipients([]string{"email:1", "email:3"}, []string{"email:1", "email:2"}))
+	assert.Equal(t, []string{}, managedRecipients([]string{"email:3"}, nil))
+}
+
+func setCheckResultPollInterval(t *testing.T, interval time.Duration) {
+	previous := checkResultPollInterval
+	checkResultPollInterval = interval
+	t.Cleanup(func() { checkResultPollInterval = previous })
+}
+
+func TestWaitForFirstResult(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setCheckResultPollInterval(t, time.Millisecond)
+
+	var calls int
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls < 3 {
+			fmt.Fprint(w, `{"token":"aaaa","down":false}`)
+			return
+		}
+		fmt.Fprint(w, `{"token":"aaaa","down":true,"last_status":502,"last_check_at":"2030-01-01T10:00:00Z"}`)
+	})
+
+	check, err := waitForFirstResult(context.Background(), client, "aaaa", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.True(t, check.Down)
+	assert.Equal(t, "HTTP status 502", checkFailure(check))
+	assert.Equal(t, "timeout", checkFailure(updown.Check{LastStatus: 0, Error: "timeout"}))
+}
+
+func TestWaitForFirstResult_Timeout(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setCheckResultPollInterval(t, 10*time.Millisecond)
+
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"token":"aaaa"}`)
+	})
+
+	_, err := waitForFirstResult(context.Background(), client, "aaaa", 50*time.Millisecond)
+	assert.EqualError(t, err, "check aaaa has not run after 50ms")
+}
+
+func TestWaitForFirstResult_Canceled(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setCheckResultPollInterval(t, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, _ *http.Request) {
+		cancel()
+		fmt.Fprint(w, `{"token":"aaaa"}`)
+	})
+
+	_, err := waitForFirstResult(ctx, client, "aaaa", time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
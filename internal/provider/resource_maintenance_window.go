@@ -0,0 +1,412 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// timeNow returns the current time, replaced in tests
+var timeNow = time.Now
+
+func maintenanceWindowResource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_maintenance_window` mutes a set of checks for a period of time, and restores their previous mute state on destroy. " +
+			"The API can only mute checks from now on: a window starting in the future is applied by the first `terraform apply` run after its start. " +
+			"Checks managed by `updown_check` should not set `mute_until`, and ignore its changes with `lifecycle { ignore_changes = [mute_until] }`.",
+
+		CreateContext: withRequestContext(maintenanceWindowCreate),
+		ReadContext:   withRequestContext(maintenanceWindowRead),
+		UpdateContext: withRequestContext(maintenanceWindowUpdate),
+		DeleteContext: withRequestContext(maintenanceWindowDelete),
+
+		CustomizeDiff: maintenanceWindowCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"checks": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Description:  "Tokens of the checks to mute.",
+				AtLeastOneOf: []string{"checks", "aliases"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"aliases": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Alias patterns of the checks to mute, such as `billing-*`, in shell glob syntax, matched against the aliases with or without the provider `alias_prefix`. They are matched when the window is applied.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Start of the window in RFC 3339 format. Defaults to the creation of the resource.",
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"end": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "End of the window in RFC 3339 format.",
+				ExactlyOneOf: []string{"end", "duration"},
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"duration": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Duration of the window from its start, such as `2h` or `90m`.",
+				ValidateFunc: validateDuration,
+			},
+			"until": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "End of the window, set as `mute_until` on the checks.",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the window is in progress and its checks are muted.",
+			},
+			"controlled_checks": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Tokens of the checks currently muted by the window.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"previous_mute_until": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Mute state of the checks before the window, by token, restored on destroy.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errs []error) {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("%q must be a positive duration such as 2h or 90m, got %q", k, v))
+	}
+	return ws, errs
+}
+
+// maintenanceWindowBounds returns the start and end of a window. The start is now when it is
+// not set yet.
+func maintenanceWindowBounds(start, end, duration string) (time.Time, time.Time, error) {
+	from := timeNow().UTC().Truncate(time.Second)
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("parsing start: %w", err)
+		}
+		from = t.UTC()
+	}
+
+	if end != "" {
+		to, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("parsing end: %w", err)
+		}
+		if !to.After(from) {
+			return time.Time{}, time.Time{}, fmt.Errorf("end %s must be after start %s", end, from.Format(time.RFC3339))
+		}
+		return from, to.UTC(), nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parsing duration: %w", err)
+	}
+	return from, from.Add(d), nil
+}
+
+// maintenanceWindowActive tells if now is between the start and the end of a window
+func maintenanceWindowActive(start, end time.Time) bool {
+	now := timeNow()
+	return !now.Before(start) && now.Before(end)
+}
+
+// maintenanceWindowCustomizeDiff plans the end of the window, and its activation or
+// deactivation when its start or end has passed since the last apply. As the window may also
+// start or end between the plan and the apply, active is then only known after apply.
+func maintenanceWindowCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("end") || !d.NewValueKnown("duration") {
+		return nil
+	}
+
+	// Without a start, the window starts when it is created
+	if !d.NewValueKnown("start") || d.Get("start").(string) == "" {
+		return d.SetNew("active", true)
+	}
+
+	start, end, err := maintenanceWindowBounds(d.Get("start").(string), d.Get("end").(string), d.Get("duration").(string))
+	if err != nil {
+		return err
+	}
+
+	if until := end.Format(time.RFC3339); d.Get("until").(string) != until {
+		if err := d.SetNew("until", until); err != nil {
+			return err
+		}
+	}
+
+	if active := maintenanceWindowActive(start, end); d.Id() == "" || d.Get("active").(bool) != active {
+		return d.SetNewComputed("active")
+	}
+
+	return nil
+}
+
+func maintenanceWindowCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(id.UniqueId())
+
+	if err := applyMaintenanceWindow(d, meta); err != nil {
+		return err
+	}
+
+	return maintenanceWindowRead(d, meta)
+}
+
+func maintenanceWindowUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := applyMaintenanceWindow(d, meta); err != nil {
+		return err
+	}
+
+	return maintenanceWindowRead(d, meta)
+}
+
+// applyMaintenanceWindow mutes the checks of an active window, and restores the checks which it
+// no longer controls
+func applyMaintenanceWindow(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*providerConfig)
+
+	start, end, err := maintenanceWindowBounds(d.Get("start").(string), d.Get("end").(string), d.Get("duration").(string))
+	if err != nil {
+		return err
+	}
+	until := end.Format(time.RFC3339)
+	active := maintenanceWindowActive(start, end)
+
+	previous := map[string]string{}
+	for k, v := range d.Get("previous_mute_until").(map[string]interface{}) {
+		previous[k] = v.(string)
+	}
+	old, _ := d.GetChange("until")
+	oldUntil := old.(string)
+	if oldUntil == "" {
+		oldUntil = until
+	}
+
+	targets := map[string]bool{}
+	if active {
+		// The alias patterns must match the checks created or renamed since the snapshot of the
+		// list cache
+		config.lists.invalidate()
+		checks, err := config.listChecks()
+		if err != nil {
+			return fmt.Errorf("reading checks from the API: %w", err)
+		}
+		if targets, err = maintenanceWindowTargets(d, config.aliasPrefix, checks); err != nil {
+			return err
+		}
+	}
+
+	// The state is saved before and after each change of the checks, so that when the API fails
+	// midway the mute state of the checks changed so far is kept, and restored on destroy
+	save := func() error {
+		for k, v := range map[string]interface{}{
+			"start":               start.Format(time.RFC3339),
+			"until":               until,
+			"active":              active,
+			"previous_mute_until": previous,
+		} {
+			if err := d.Set(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := save(); err != nil {
+		return err
+	}
+
+	if err := restoreMutedChecks(meta, previous, oldUntil, targets, save); err != nil {
+		return err
+	}
+
+	tokens := make([]string, 0, len(targets))
+	for token := range targets {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		if err := muteCheck(meta, token, until, previous); err != nil {
+			return err
+		}
+		if err := save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maintenanceWindowTargets returns the tokens of the checks designated by the tokens and alias
+// patterns of a window. The patterns match the aliases with or without the provider prefix.
+func maintenanceWindowTargets(d *schema.ResourceData, prefix string, checks []updown.Check) (map[string]bool, error) {
+	targets := map[string]bool{}
+
+	tokens := map[string]bool{}
+	for _, c := range checks {
+		tokens[c.Token] = true
+	}
+	for _, token := range setToStringSlice(d.Get("checks").(*schema.Set)) {
+		if !tokens[token] {
+			return nil, fmt.Errorf("check %s not found", token)
+		}
+		targets[token] = true
+	}
+
+	for _, pattern := range setToStringSlice(d.Get("aliases").(*schema.Set)) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid alias pattern %q: %w", pattern, err)
+		}
+		for _, c := range checks {
+			alias, _ := unmarkAlias(prefix, c.Alias)
+			matched, _ := path.Match(pattern, alias)
+			if full, _ := path.Match(pattern, c.Alias); matched || full {
+				targets[c.Token] = true
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// muteCheck mutes a check until the end of the window, recording its previous mute state the
+// first time
+func muteCheck(meta interface{}, token, until string, previous map[string]string) error {
+	config := meta.(*providerConfig)
+
+	config.checkLocks.Lock(token)
+	defer config.checkLocks.Unlock(token)
+
+	check, _, err := config.client.Check.Get(token)
+	if err != nil {
+		return fmt.Errorf("reading check from the API: %w", err)
+	}
+
+	if normalizeMuteUntil(check.MuteUntil) == until {
+		return nil
+	}
+	if _, ok := previous[token]; !ok {
+		previous[token] = check.MuteUntil
+	}
+
+	payload := checkItemFromCheck(check)
+	payload.MuteUntil = until
+	if _, _, err := config.client.Check.Update(token, payload); err != nil {
+		return fmt.Errorf("muting check %s with the API: %w", token, err)
+	}
+
+	return nil
+}
+
+// restoreMutedChecks restores the previous mute state of the checks muted by the window, except
+// the ones to keep, calling saved after each of them. Checks whose mute state was changed since,
+// or which were deleted, are left alone.
+func restoreMutedChecks(meta interface{}, previous map[string]string, until string, keep map[string]bool, saved func() error) error {
+	config := meta.(*providerConfig)
+
+	for token, muteUntil := range previous {
+		if keep[token] {
+			continue
+		}
+
+		if err := restoreMutedCheck(config, token, muteUntil, until); err != nil {
+			return err
+		}
+		delete(previous, token)
+		if err := saved(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreMutedCheck(config *providerConfig, token, muteUntil, until string) error {
+	config.checkLocks.Lock(token)
+	defer config.checkLocks.Unlock(token)
+
+	check, _, err := config.client.Check.Get(token)
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading check from the API: %w", err)
+	}
+
+	if normalizeMuteUntil(check.MuteUntil) != until {
+		return nil
+	}
+
+	// The API refuses to mute checks until a time which has passed
+	if t, err := time.Parse(time.RFC3339, normalizeMuteUntil(muteUntil)); err == nil && !t.After(timeNow()) {
+		muteUntil = ""
+	}
+
+	payload := checkItemFromCheck(check)
+	payload.MuteUntil = muteUntil
+	if _, _, err := config.client.Check.Update(token, payload); err != nil {
+		return fmt.Errorf("restoring the mute state of check %s with the API: %w", token, err)
+	}
+
+	return nil
+}
+
+func maintenanceWindowRead(d *schema.ResourceData, meta interface{}) error {
+	checks, err := meta.(*providerConfig).listChecks()
+	if err != nil {
+		return fmt.Errorf("reading checks from the API: %w", err)
+	}
+
+	previous := d.Get("previous_mute_until").(map[string]interface{})
+	until := d.Get("until").(string)
+
+	controlled := []string{}
+	for _, c := range checks {
+		if _, ok := previous[c.Token]; ok && normalizeMuteUntil(c.MuteUntil) == until {
+			controlled = append(controlled, c.Token)
+		}
+	}
+
+	return d.Set("controlled_checks", controlled)
+}
+
+func maintenanceWindowDelete(d *schema.ResourceData, meta interface{}) error {
+	previous := map[string]string{}
+	for k, v := range d.Get("previous_mute_until").(map[string]interface{}) {
+		previous[k] = v.(string)
+	}
+
+	return restoreMutedChecks(meta, previous, d.Get("until").(string), nil, func() error {
+		return d.Set("previous_mute_until", previous)
+	})
+}
@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handleMutableChecks serves checks whose mute_until can be updated, and returns a function
+// giving the current mute_until of each check
+func handleMutableChecks(t *testing.T, mux *http.ServeMux, checks map[string]*updown.Check) func() map[string]string {
+	var mu sync.Mutex
+
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var res []updown.Check
+		for _, c := range checks {
+			res = append(res, *c)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	})
+	mux.HandleFunc("/checks/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		c, ok := checks[strings.TrimPrefix(r.URL.Path, "/checks/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Not found"}`))
+			return
+		}
+		if r.Method == "PUT" {
+			var item updown.CheckItem
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			assert.Equal(t, c.Alias, item.Alias)
+			c.MuteUntil = item.MuteUntil
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(c))
+	})
+
+	return func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		res := map[string]string{}
+		for token, c := range checks {
+			res[token] = c.MuteUntil
+		}
+		return res
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	muteUntil := handleMutableChecks(t, mux, map[string]*updown.Check{
+		"aaaa": {Token: "aaaa", Alias: "billing-api", MuteUntil: "recovery"},
+		"bbbb": {Token: "bbbb", Alias: "billing-web"},
+		"cccc": {Token: "cccc", Alias: "website"},
+		"dddd": {Token: "dddd", Alias: "blog", MuteUntil: "2030-01-01T10:00:00.000Z"},
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	d := schema.TestResourceDataRaw(t, maintenanceWindowResource().Schema, map[string]interface{}{
+		"checks":   []interface{}{"dddd"},
+		"aliases":  []interface{}{"billing-*"},
+		"duration": "2h",
+	})
+	require.NoError(t, maintenanceWindowCreate(d, meta))
+	assert.Equal(t, "2030-01-01T08:00:00Z", d.Get("start"))
+	assert.Equal(t, "2030-01-01T10:00:00Z", d.Get("until"))
+	assert.True(t, d.Get("active").(bool))
+	assert.Equal(t, map[string]string{
+		"aaaa": "2030-01-01T10:00:00Z",
+		"bbbb": "2030-01-01T10:00:00Z",
+		"cccc": "",
+		"dddd": "2030-01-01T10:00:00.000Z",
+	}, muteUntil())
+	// dddd was already muted until the end of the window
+	assert.Equal(t, map[string]interface{}{"aaaa": "recovery", "bbbb": ""}, d.Get("previous_mute_until"))
+	assert.ElementsMatch(t, []interface{}{"aaaa", "bbbb"}, d.Get("controlled_checks").(*schema.Set).List())
+
+	require.NoError(t, maintenanceWindowDelete(d, meta))
+	assert.Equal(t, map[string]string{
+		"aaaa": "recovery",
+		"bbbb": "",
+		"cccc": "",
+		"dddd": "2030-01-01T10:00:00.000Z",
+	}, muteUntil())
+}
+
+func TestMaintenanceWindow_Scheduled(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	muteUntil := handleMutableChecks(t, mux, map[string]*updown.Check{
+		"aaaa": {Token: "aaaa", Alias: "billing-api"},
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	d := schema.TestResourceDataRaw(t, maintenanceWindowResource().Schema, map[string]interface{}{
+		"checks": []interface{}{"aaaa"},
+		"start":  "2030-01-02T00:00:00Z",
+		"end":    "2030-01-02T02:00:00Z",
+	})
+	require.NoError(t, maintenanceWindowCreate(d, meta))
+	assert.False(t, d.Get("active").(bool))
+	assert.Equal(t, map[string]string{"aaaa": ""}, muteUntil())
+
+	now = time.Date(2030, 1, 2, 1, 0, 0, 0, time.UTC)
+	require.NoError(t, maintenanceWindowUpdate(d, meta))
+	assert.True(t, d.Get("active").(bool))
+	assert.Equal(t, map[string]string{"aaaa": "2030-01-02T02:00:00Z"}, muteUntil())
+
+	// The window ends and restores the mute state
+	now = time.Date(2030, 1, 2, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, maintenanceWindowUpdate(d, meta))
+	assert.False(t, d.Get("active").(bool))
+	assert.Empty(t, d.Get("previous_mute_until"))
+	assert.Equal(t, map[string]string{"aaaa": ""}, muteUntil())
+}
+
+func TestMaintenanceWindow_PartialFailure(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	muteUntil := handleMutableChecks(t, mux, map[string]*updown.Check{
+		"aaaa": {Token: "aaaa", Alias: "billing-api", MuteUntil: "recovery"},
+		"bbbb": {Token: "bbbb", Alias: "billing-web"},
+		"cccc": {Token: "cccc", Alias: "billing-worker"},
+	})
+	// The API fails to mute the second check
+	mux.HandleFunc("/checks/bbbb", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"token":"bbbb","alias":"billing-web"}`))
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	d := schema.TestResourceDataRaw(t, maintenanceWindowResource().Schema, map[string]interface{}{
+		"aliases":  []interface{}{"billing-*"},
+		"duration": "2h",
+	})
+	assert.ErrorContains(t, maintenanceWindowCreate(d, meta), "muting check bbbb with the API")
+	assert.Equal(t, map[string]string{"aaaa": "2030-01-01T10:00:00Z", "bbbb": "", "cccc": ""}, muteUntil())
+
+	// The state keeps the mute state of the check muted before the failure
+	assert.Equal(t, "2030-01-01T10:00:00Z", d.Get("until"))
+	assert.True(t, d.Get("active").(bool))
+	assert.Equal(t, map[string]interface{}{"aaaa": "recovery"}, d.Get("previous_mute_until"))
+
+	require.NoError(t, maintenanceWindowDelete(d, meta))
+	assert.Equal(t, map[string]string{"aaaa": "recovery", "bbbb": "", "cccc": ""}, muteUntil())
+}
+
+func TestMaintenanceWindow_AliasPrefix(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	muteUntil := handleMutableChecks(t, mux, map[string]*updown.Check{
+		"aaaa": {Token: "aaaa", Alias: "[tf] billing-api"},
+		"bbbb": {Token: "bbbb", Alias: "billing-web"},
+		"cccc": {Token: "cccc", Alias: "[tf] website"},
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV(), aliasPrefix: "[tf] "}
+
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	d := schema.TestResourceDataRaw(t, maintenanceWindowResource().Schema, map[string]interface{}{
+		"aliases":  []interface{}{"billing-*", "[[]tf] website"},
+		"duration": "2h",
+	})
+	require.NoError(t, maintenanceWindowCreate(d, meta))
+	assert.Equal(t, map[string]string{
+		"aaaa": "2030-01-01T10:00:00Z",
+		"bbbb": "2030-01-01T10:00:00Z",
+		"cccc": "2030-01-01T10:00:00Z",
+	}, muteUntil())
+}
+
+func TestMaintenanceWindowCustomizeDiff(t *testing.T) {
+	now := time.Date(2030, 1, 1, 23, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	config := map[string]cty.Value{
+		"checks": stringSet("aaaa"),
+		"start":  cty.StringVal("2030-01-02T00:00:00Z"),
+		"end":    cty.StringVal("2030-01-02T02:00:00Z"),
+	}
+	state := map[string]string{
+		"id":     "aaaa",
+		"start":  "2030-01-02T00:00:00Z",
+		"end":    "2030-01-02T02:00:00Z",
+		"until":  "2030-01-02T02:00:00Z",
+		"active": "false",
+	}
+
+	_, diff := planDiff(t, maintenanceWindowResource(), config, state, nil)
+	assert.NotContains(t, diff.Attributes, "active")
+
+	// The window may start or end before the apply, active is only known then
+	now = time.Date(2030, 1, 2, 1, 0, 0, 0, time.UTC)
+	_, diff = planDiff(t, maintenanceWindowResource(), config, state, nil)
+	require.Contains(t, diff.Attributes, "active")
+	assert.True(t, diff.Attributes["active"].NewComputed)
+
+	// A window without start starts when it is created
+	_, diff = planDiff(t, maintenanceWindowResource(), map[string]cty.Value{
+		"checks":   stringSet("aaaa"),
+		"duration": cty.StringVal("2h"),
+	}, nil, nil)
+	require.Contains(t, diff.Attributes, "active")
+	assert.Equal(t, "true", diff.Attributes["active"].New)
+}
+
+func TestMaintenanceWindowDelete_ChangedOutside(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	muteUntil := handleMutableChecks(t, mux, map[string]*updown.Check{
+		"aaaa": {Token: "aaaa", MuteUntil: "forever"},
+		"bbbb": {Token: "bbbb", MuteUntil: "2030-01-01T10:00:00Z"},
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	d := schema.TestResourceDataRaw(t, maintenanceWindowResource().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("until", "2030-01-01T10:00:00Z"))
+	require.NoError(t, d.Set("previous_mute_until", map[string]interface{}{"aaaa": "", "bbbb": "recovery", "zzzz": ""}))
+
+	// aaaa was muted outside of the window since, and zzzz was deleted
+	require.NoError(t, maintenanceWindowDelete(d, meta))
+	assert.Equal(t, map[string]string{"aaaa": "forever", "bbbb": "recovery"}, muteUntil())
+}
+
+func TestMaintenanceWindowBounds(t *testing.T) {
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	start, end, err := maintenanceWindowBounds("", "", "90m")
+	require.NoError(t, err)
+	assert.Equal(t, now, start)
+	assert.Equal(t, now.Add(90*time.Minute), end)
+
+	start, end, err = maintenanceWindowBounds("2030-01-02T00:00:00+01:00", "2030-01-02T02:00:00Z", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, 1, 1, 23, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2030, 1, 2, 2, 0, 0, 0, time.UTC), end)
+	assert.False(t, maintenanceWindowActive(start, end))
+
+	_, _, err = maintenanceWindowBounds("2030-01-02T00:00:00Z", "2030-01-01T00:00:00Z", "")
+	assert.EqualError(t, err, "end 2030-01-01T00:00:00Z must be after start 2030-01-02T00:00:00Z")
+}
@@ -2,28 +2,41 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// Number of attempts, and base wait between them, at restoring the enabled flag of a pulse
+// check after recovering its URL
+var (
+	pulseRestoreAttempts = 3
+	pulseRestoreWait     = time.Second
+)
+
 func pulseResource() *schema.Resource {
 	return &schema.Resource{
 		Description: "`updown_pulse` defines a pulse (heartbeat) check for monitoring scheduled jobs and cron tasks",
 
-		Create: pulseCreate,
-		Read:   pulseRead,
-		Delete: pulseDelete,
-		Update: pulseUpdate,
-		Exists: pulseExists,
+		CreateContext: pulseCreate,
+		ReadContext:   pulseRead,
+		DeleteContext: pulseDelete,
+		UpdateContext: pulseUpdate,
+
+		CustomizeDiff: customdiff.All(pulseCustomizeDiff, aliasPrefixCustomizeDiff),
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: pulseImport,
 		},
 
-		Schema: map[string]*schema.Schema{
+		Schema: withDestroySchema(map[string]*schema.Schema{
 			"alias": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -47,26 +60,36 @@ func pulseResource() *schema.Resource {
 				Default:     false,
 			},
 			"mute_until": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Mute notifications until given time, accepts a time, 'recovery' or 'forever'.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Mute notifications until given time, accepts a time, 'recovery' or 'forever'.",
+				DiffSuppressFunc: suppressEquivalentMuteUntil,
 			},
 			"recipients": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Selected alert recipients. It's an array of recipient IDs you can get from the recipients API.",
+				Computed:    true,
+				Description: "Selected alert recipients. It's an array of recipient IDs you can get from the recipients API or the `updown_recipients` data source, including the integrations set up from the web UI. Defaults to the provider `defaults`.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"ignore_external_recipients": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave alone the recipients attached to the pulse outside of this resource, such as with `updown_check_recipient`, rather than removing them. Enable it before attaching such recipients, as the ones already read into `recipients` are considered managed by the resource.",
+			},
+			"marked": markedSchema(),
 			"pulse_url": {
 				Type:     schema.TypeString,
 				Computed: true,
 				Description: "The URL to POST heartbeats to. Your scheduled job should POST to this URL on each successful run. " +
-					"Note: the updown.io API redacts the secret key in GET responses. On import, the provider " +
-					"toggles the enabled flag to force a real update and recover the full URL.",
+					"Note: the updown.io API redacts the secret key in GET responses. On import, pass the known URL in " +
+					"the `<token>,<pulse_url>` ID, or set `allow_pulse_url_recovery` on the provider to let it toggle " +
+					"the enabled flag in order to force a real update and recover the full URL.",
 			},
-		},
+		}),
 	}
 }
 
@@ -107,112 +130,172 @@ func constructPulsePayload(d *schema.ResourceData) updown.CheckItem {
 	return payload
 }
 
-func pulseCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+func pulseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*providerConfig)
 
-	check, _, err := client.Check.Add(constructPulsePayload(d))
+	payload := constructPulsePayload(d)
+	payload.Alias = config.aliasPrefix + payload.Alias
+
+	check, _, err := config.client.WithContext(ctx).Check.Add(payload)
 	if err != nil {
-		return fmt.Errorf("creating pulse check with the API: %s", err.Error())
+		return diag.Errorf("creating pulse check with the API: %s", err.Error())
 	}
 
 	d.SetId(check.Token)
 	if err := d.Set("pulse_url", check.URL); err != nil {
-		return fmt.Errorf("setting pulse_url: %s", err.Error())
+		return diag.Errorf("setting pulse_url: %s", err.Error())
 	}
 
-	return pulseRead(d, meta)
+	return pulseRead(ctx, d, meta)
 }
 
-func pulseRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	check, _, err := client.Check.Get(d.Id())
+func pulseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*providerConfig)
+	check, _, err := config.client.WithContext(ctx).Check.Get(d.Id())
 
 	if err != nil {
-		return fmt.Errorf("reading pulse check from the API: %s", err.Error())
+		return diag.Errorf("reading pulse check from the API: %s", err.Error())
 	}
 
 	// Verify this is actually a pulse check
 	if check.Type != "pulse" {
-		return fmt.Errorf("check %s is not a pulse check (type: %s)", d.Id(), check.Type)
+		return diag.Errorf("check %s is not a pulse check (type: %s)", d.Id(), check.Type)
+	}
+
+	alias, marked := unmarkAlias(config.aliasPrefix, check.Alias)
+
+	recipients := check.RecipientIDs
+	if d.Get("ignore_external_recipients").(bool) {
+		recipients = managedRecipients(recipients, setToStringSlice(d.Get("recipients").(*schema.Set)))
 	}
 
 	for k, v := range map[string]interface{}{
-		"alias":      check.Alias,
+		"alias":      alias,
+		"marked":     marked,
 		"period":     check.Period,
 		"enabled":    check.Enabled,
 		"published":  check.Published,
 		"mute_until": check.MuteUntil,
-		"recipients": check.RecipientIDs,
+		"recipients": recipients,
 	} {
 		if err := d.Set(k, v); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	// The API redacts the pulse URL secret key on GET requests. If the state
-	// already holds the full URL (from create or a previous update), preserve it.
-	// On import, the state is empty so we need to recover the full URL.
-	// The updown.io API only returns the unredacted pulse URL when a field
-	// actually changes. Toggle `enabled` to force a real change, capture
-	// the full URL, then restore the original value.
+	// already holds the full URL (from create, a previous update or an import
+	// ID carrying it), preserve it.
 	currentURL := d.Get("pulse_url").(string)
-	if currentURL == "" || strings.Contains(currentURL, "<redacted>") {
-		payload := updown.CheckItem{
-			Type:         check.Type,
-			Period:       check.Period,
-			Enabled:      !check.Enabled,
-			Published:    check.Published,
-			Alias:        check.Alias,
-			StringMatch:  check.StringMatch,
-			MuteUntil:    check.MuteUntil,
-			RecipientIDs: check.RecipientIDs,
-		}
-		updated, _, err := client.Check.Update(d.Id(), payload)
-		if err != nil {
-			return fmt.Errorf("recovering full pulse URL via update: %s", err.Error())
-		}
+	if currentURL != "" && !isRedactedPulseURL(currentURL) {
+		return nil
+	}
 
-		// Restore original enabled value.
-		payload.Enabled = check.Enabled
-		if _, _, err := client.Check.Update(d.Id(), payload); err != nil {
-			return fmt.Errorf("restoring enabled flag after pulse URL recovery: %s", err.Error())
+	if !config.allowPulseURLRecovery {
+		// Warned on every refresh, as the redacted URL kept in the state cannot be used
+		if err := d.Set("pulse_url", check.URL); err != nil {
+			return diag.Errorf("setting pulse_url: %s", err.Error())
 		}
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Pulse URL cannot be recovered safely",
+			Detail: fmt.Sprintf("The updown.io API redacts the secret part of the URL of pulse check %s. "+
+				"Import it again with the `%s,<pulse_url>` ID, or set `allow_pulse_url_recovery` on the "+
+				"provider to let it toggle the enabled flag of the check in order to recover the URL.", d.Id(), d.Id()),
+			AttributePath: cty.GetAttrPath("pulse_url"),
+		}}
+	}
 
-		if err := d.Set("pulse_url", updated.URL); err != nil {
-			return fmt.Errorf("setting pulse_url: %s", err.Error())
-		}
+	// The restore must not be canceled, not to leave the check with the enabled flag toggled
+	pulseURL, err := recoverPulseURL(config.client.WithContext(context.WithoutCancel(ctx)), check)
+	if pulseURL == "" {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("pulse_url", pulseURL); err != nil {
+		return diag.Errorf("setting pulse_url: %s", err.Error())
+	}
+
+	// The state is dropped when reading fails, so only restoring the enabled flag failing is a
+	// warning: the recovered URL is kept, and the next refresh does not toggle the flag again
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       "Pulse URL recovered, but the enabled flag could not be restored",
+			Detail:        err.Error(),
+			AttributePath: cty.GetAttrPath("enabled"),
+		}}
 	}
 
 	return nil
 }
 
-func pulseUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-
-	_, _, err := client.Check.Update(d.Id(), constructPulsePayload(d))
+// recoverPulseURL returns the unredacted URL of a pulse check. The updown.io API only returns
+// it when a field actually changes, so it toggles `enabled` to force a real change, captures
+// the full URL, then restores the original value. The restore is retried so that the check is
+// not left in the wrong state after a transient failure. When it still fails, the recovered URL
+// is returned along with the error.
+func recoverPulseURL(client *updown.Client, check updown.Check) (string, error) {
+	payload := checkItemFromCheck(check)
+	payload.Enabled = !check.Enabled
+	updated, _, err := client.Check.Update(check.Token, payload)
 	if err != nil {
-		return fmt.Errorf("updating pulse check with the API: %s", err.Error())
+		return "", fmt.Errorf("recovering full pulse URL via update: %s", err.Error())
 	}
 
-	return pulseRead(d, meta)
+	// Restore original enabled value.
+	payload.Enabled = check.Enabled
+	for attempt := 1; ; attempt++ {
+		_, _, err = client.Check.Update(check.Token, payload)
+		if err == nil {
+			break
+		}
+		if attempt == pulseRestoreAttempts {
+			return updated.URL, fmt.Errorf("restoring enabled flag after pulse URL recovery, pulse check %s was left with enabled=%t "+
+				"and must be fixed manually: %s", check.Token, !check.Enabled, err.Error())
+		}
+		time.Sleep(time.Duration(attempt) * pulseRestoreWait)
+	}
+
+	return updated.URL, nil
 }
 
-func pulseDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	checkDeleted, _, err := client.Check.Remove(d.Id())
+// isRedactedPulseURL tells if the secret part of a pulse URL was redacted by the API
+func isRedactedPulseURL(u string) bool {
+	return strings.Contains(u, "<redacted>")
+}
 
-	if err != nil {
-		return fmt.Errorf("removing pulse check from the API: %s", err.Error())
+func pulseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*providerConfig)
+	client := config.client.WithContext(ctx)
+
+	// Not to race with the recipients attached by updown_check_recipient
+	config.checkLocks.Lock(d.Id())
+	defer config.checkLocks.Unlock(d.Id())
+
+	payload := constructPulsePayload(d)
+	payload.Alias = config.aliasPrefix + payload.Alias
+	if d.HasChange("recipients") {
+		// Send an empty list rather than none when every recipient is removed
+		payload.RecipientIDs = setToStringSlice(d.Get("recipients").(*schema.Set))
 	}
 
-	if !checkDeleted {
-		return fmt.Errorf("pulse check couldn't be deleted")
+	if d.Get("ignore_external_recipients").(bool) {
+		check, _, err := client.Check.Get(d.Id())
+		if err != nil {
+			return diag.Errorf("reading pulse check from the API: %s", err.Error())
+		}
+		old, _ := d.GetChange("recipients")
+		payload.RecipientIDs = mergeExternalRecipients(check.RecipientIDs, setToStringSlice(old.(*schema.Set)), payload.RecipientIDs)
 	}
 
-	return nil
+	_, _, err := client.Check.Update(d.Id(), payload)
+	if err != nil {
+		return diag.Errorf("updating pulse check with the API: %s", err.Error())
+	}
+
+	return pulseRead(ctx, d, meta)
 }
 
-func pulseExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	err := pulseRead(d, meta)
-	return err == nil, err
+func pulseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(destroyCheck(d, meta.(*providerConfig).withContext(ctx), "pulse check"))
 }
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setPulseRestoreWait(t *testing.T, wait time.Duration) {
+	previous := pulseRestoreWait
+	pulseRestoreWait = wait
+	t.Cleanup(func() { pulseRestoreWait = previous })
+}
+
+func TestRecoverPulseURL(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setPulseRestoreWait(t, time.Millisecond)
+
+	var enabled []bool
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, r *http.Request) {
+		var item updown.CheckItem
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+		enabled = append(enabled, item.Enabled)
+
+		// Fail the first restore attempt
+		if len(enabled) == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"dddd","type":"pulse","enabled":%t,"url":"https://pulse.updown.io/dddd/secret"}`, item.Enabled)
+	})
+
+	pulseURL, err := recoverPulseURL(client, updown.Check{Token: "dddd", Type: "pulse", Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "https://pulse.updown.io/dddd/secret", pulseURL)
+	assert.Equal(t, []bool{false, true, true}, enabled)
+}
+
+func TestRecoverPulseURL_RestoreFails(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setPulseRestoreWait(t, time.Millisecond)
+
+	var calls int
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"token":"dddd","type":"pulse","url":"https://pulse.updown.io/dddd/secret"}`)
+	})
+
+	pulseURL, err := recoverPulseURL(client, updown.Check{Token: "dddd", Type: "pulse", Enabled: true})
+	assert.ErrorContains(t, err, "pulse check dddd was left with enabled=false and must be fixed manually")
+	assert.Equal(t, "https://pulse.updown.io/dddd/secret", pulseURL)
+	assert.Equal(t, 1+pulseRestoreAttempts, calls)
+}
+
+func TestPulseRead_RecoveryRestoreFails(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	setPulseRestoreWait(t, time.Millisecond)
+
+	var updates int
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"token":"dddd","type":"pulse","enabled":true,"period":3600,"url":"https://pulse.updown.io/dddd/<redacted>"}`)
+			return
+		}
+		updates++
+		if updates > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"token":"dddd","type":"pulse","enabled":false,"url":"https://pulse.updown.io/dddd/secret"}`)
+	})
+
+	d := schema.TestResourceDataRaw(t, pulseResource().Schema, map[string]interface{}{})
+	d.SetId("dddd")
+	diags := pulseRead(context.Background(), d, &providerConfig{client: client, allowPulseURLRecovery: true})
+
+	// A warning rather than an error, so that the state holding the recovered URL is kept
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Detail, "was left with enabled=false")
+	assert.Equal(t, "https://pulse.updown.io/dddd/secret", d.Get("pulse_url"))
+
+	// The next refresh keeps the URL, without toggling the flag again
+	toggles := updates
+	require.Empty(t, pulseRead(context.Background(), d, &providerConfig{client: client, allowPulseURLRecovery: true}))
+	assert.Equal(t, toggles, updates)
+}
+
+func TestPulseRead_RecoveryFails(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"token":"dddd","type":"pulse","enabled":true,"period":3600,"url":"https://pulse.updown.io/dddd/<redacted>"}`)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+
+	d := schema.TestResourceDataRaw(t, pulseResource().Schema, map[string]interface{}{})
+	d.SetId("dddd")
+	diags := pulseRead(context.Background(), d, &providerConfig{client: client, allowPulseURLRecovery: true})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "recovering full pulse URL")
+}
+
+func TestPulseRead_RedactedURLWarning(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	mux.HandleFunc("/checks/dddd", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"token":"dddd","type":"pulse","enabled":true,"period":3600,"url":"https://pulse.updown.io/dddd/<redacted>"}`)
+	})
+	config := &providerConfig{client: client}
+
+	d := schema.TestResourceDataRaw(t, pulseResource().Schema, map[string]interface{}{})
+	d.SetId("dddd")
+
+	// The warning is emitted on every refresh while the state holds the redacted URL
+	for i := 0; i < 2; i++ {
+		diags := pulseRead(context.Background(), d, config)
+		require.Len(t, diags, 1)
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "https://pulse.updown.io/dddd/<redacted>", d.Get("pulse_url"))
+	}
+}
+
+func TestPulseUpdate_IgnoreExternalRecipients(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	recipients := []string{"email:1", "slack:2"}
+	mux.HandleFunc("/checks/aaaa", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var item updown.CheckItem
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			recipients = item.RecipientIDs
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(updown.Check{
+			Token: "aaaa", Type: "pulse", Period: 3600, Enabled: true,
+			URL: "https://pulse.updown.io/aaaa/secret", RecipientIDs: recipients,
+		}))
+	})
+	meta := &providerConfig{client: client, checkLocks: newMutexKV()}
+
+	// slack:2 was attached with updown_check_recipient
+	d := plan(t, pulseResource(), map[string]cty.Value{
+		"period":                     cty.NumberIntVal(3600),
+		"recipients":                 stringSet("email:3"),
+		"ignore_external_recipients": cty.True,
+	}, map[string]string{
+		"id":                         "aaaa",
+		"period":                     "3600",
+		"enabled":                    "true",
+		"ignore_external_recipients": "true",
+		"pulse_url":                  "https://pulse.updown.io/aaaa/secret",
+		"recipients.#":               "1",
+		fmt.Sprintf("recipients.%d", schema.HashSchema(&schema.Schema{Type: schema.TypeString})("email:1")): "email:1",
+	}, meta)
+
+	require.Empty(t, pulseUpdate(context.Background(), d, meta))
+	assert.Equal(t, []string{"email:3", "slack:2"}, recipients)
+	assert.Equal(t, []string{"email:3"}, setToStringSlice(d.Get("recipients").(*schema.Set)))
+}
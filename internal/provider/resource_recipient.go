@@ -2,37 +2,72 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
 
 	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// e164 matches phone numbers in the E.164 international format
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
 func recipientResource() *schema.Resource {
 	return &schema.Resource{
 		Description: "`updown_recipient` defines a recipient",
 
-		Create: recipientCreate,
-		Read:   recipientRead,
-		Delete: recipientDelete,
-		Exists: recipientExists,
+		CreateContext: withRequestContext(recipientCreate),
+		ReadContext:   withRequestContext(recipientRead),
+		UpdateContext: withRequestContext(recipientUpdate),
+		DeleteContext: withRequestContext(recipientDelete),
+
+		CustomizeDiff: recipientCustomizeDiff,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: recipientImport,
 		},
 
 		Schema: map[string]*schema.Schema{
 			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Type of recipient ('email', 'sms', 'webhook', 'slack_compatible' or 'msteams' only). The other integrations (slack, telegram, zapier, statuspage, etc.) require the web UI to setup.",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(recipientTypeNames(), false),
+			},
+			"value": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "Type of recipient ('email', 'sms', 'webhook' or 'slack_compatible' only). The other integrations (slack, telegram, zapier, statuspage, etc.) require the web UI to setup.",
+				Description: "The recipient value (email address, phone number in E.164 format such as +33612345678, or https URL)",
 				ForceNew:    true,
 			},
-			"value": {
+			"name": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The recipient value (email address, phone number or URL)",
+				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
+				Description: "User-friendly label of webhook recipients. For the other types, the API returns the value.",
+			},
+			"selected": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Select the recipient on all the existing checks when it is created. Changing it afterwards has no effect.",
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reuse an existing recipient with the same type and value instead of creating a duplicate. As it may be shared with checks outside of this configuration, the adopted recipient is only removed from the state on destroy, not deleted.",
+			},
+			"adopted": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether an existing recipient was adopted on creation with `adopt_existing`, in which case it is not deleted on destroy.",
 			},
 		},
 	}
@@ -48,11 +83,83 @@ func constructRecipientPayload(d *schema.ResourceData) updown.RecipientItem {
 		payload.Value = v.(string)
 	}
 
+	if v, ok := d.GetOk("name"); ok {
+		payload.Name = v.(string)
+	}
+
+	if v, ok := d.GetOk("selected"); ok {
+		payload.Selected = v.(bool)
+	}
+
 	return payload
 }
 
+// recipientTypeNames returns the names of the recipient types which can be created
+func recipientTypeNames() []string {
+	names := make([]string, len(updown.RecipientTypes))
+	for i, t := range updown.RecipientTypes {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// recipientCustomizeDiff validates the value, and name, of a recipient against its type
+func recipientCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("value") {
+		return nil
+	}
+
+	recipientType := updown.RecipientType(d.Get("type").(string))
+	if err := validateRecipientValue(recipientType, d.Get("value").(string)); err != nil {
+		return err
+	}
+
+	config := d.GetRawConfig()
+	if recipientType != updown.RecipientTypeWebhook && !config.IsNull() && !config.GetAttr("name").IsNull() {
+		return fmt.Errorf("name can only be set on webhook recipients")
+	}
+
+	return nil
+}
+
+// validateRecipientValue checks that value is an email address, an E.164 phone number or an
+// https URL depending on the recipient type
+func validateRecipientValue(recipientType updown.RecipientType, value string) error {
+	switch recipientType {
+	case updown.RecipientTypeEmail:
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return fmt.Errorf("value %q is not a valid email address", value)
+		}
+	case updown.RecipientTypeSMS:
+		if !e164.MatchString(value) {
+			return fmt.Errorf("value %q is not a phone number in E.164 format, such as +33612345678", value)
+		}
+	case updown.RecipientTypeWebhook, updown.RecipientTypeSlackCompatible, updown.RecipientTypeMSTeams:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("value %q is not a valid https URL", value)
+		}
+	}
+	return nil
+}
+
 func recipientCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	client := meta.(*providerConfig).client
+
+	if d.Get("adopt_existing").(bool) {
+		existing, found, err := findExistingRecipient(meta.(*providerConfig), constructRecipientPayload(d))
+		if err != nil {
+			return err
+		}
+		if found {
+			d.SetId(existing.ID)
+			if err := d.Set("adopted", true); err != nil {
+				return err
+			}
+			return recipientRead(d, meta)
+		}
+	}
 
 	recipient, _, err := client.Recipient.Add(constructRecipientPayload(d))
 	if err != nil {
@@ -60,13 +167,15 @@ func recipientCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.SetId(recipient.ID)
+	if err := d.Set("adopted", false); err != nil {
+		return err
+	}
 
 	return recipientRead(d, meta)
 }
 
 func recipientRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	recipients, _, err := client.Recipient.List()
+	recipients, err := meta.(*providerConfig).listRecipients()
 
 	if err != nil {
 		return fmt.Errorf("reading recipients from the API: %w", err)
@@ -77,6 +186,7 @@ func recipientRead(d *schema.ResourceData, meta interface{}) error {
 			for k, v := range map[string]interface{}{
 				"type":  string(r.Type),
 				"value": r.Value,
+				"name":  r.Name,
 			} {
 				if err := d.Set(k, v); err != nil {
 					return err
@@ -88,8 +198,35 @@ func recipientRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// recipientUpdate only handles adopt_existing and selected, which are only used on creation.
+// Every other attribute forces a new recipient.
+func recipientUpdate(d *schema.ResourceData, meta interface{}) error {
+	return recipientRead(d, meta)
+}
+
+// findExistingRecipient looks for a recipient with the same type and value as payload
+func findExistingRecipient(config *providerConfig, payload updown.RecipientItem) (updown.Recipient, bool, error) {
+	recipients, err := config.listRecipients()
+	if err != nil {
+		return updown.Recipient{}, false, fmt.Errorf("reading recipients from the API: %w", err)
+	}
+
+	for _, r := range recipients {
+		if recipientMatches(r, payload.Type, payload.Value) {
+			return r, true, nil
+		}
+	}
+
+	return updown.Recipient{}, false, nil
+}
+
 func recipientDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	// Adopted recipients were not created by this resource, and may be used elsewhere
+	if d.Get("adopted").(bool) {
+		return nil
+	}
+
+	client := meta.(*providerConfig).client
 	RecipientDeleted, _, err := client.Recipient.Remove(d.Id())
 
 	if err != nil {
@@ -102,8 +239,3 @@ func recipientDelete(d *schema.ResourceData, meta interface{}) error {
 
 	return nil
 }
-
-func recipientExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	err := recipientRead(d, meta)
-	return err == nil, err
-}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRecipientValue(t *testing.T) {
+	for _, tc := range []struct {
+		recipientType updown.RecipientType
+		value         string
+		valid         bool
+	}{
+		{updown.RecipientTypeEmail, "ops@example.com", true},
+		{updown.RecipientTypeEmail, "Ops <ops@example.com>", false},
+		{updown.RecipientTypeEmail, "not-an-email", false},
+		{updown.RecipientTypeSMS, "+33612345678", true},
+		{updown.RecipientTypeSMS, "0612345678", false},
+		{updown.RecipientTypeSMS, "+33 6 12 34 56 78", false},
+		{updown.RecipientTypeWebhook, "https://example.com/hook", true},
+		{updown.RecipientTypeWebhook, "http://example.com/hook", false},
+		{updown.RecipientTypeSlackCompatible, "https://hooks.slack.com/services/T/B/X", true},
+		{updown.RecipientTypeMSTeams, "https:///no-host", false},
+	} {
+		err := validateRecipientValue(tc.recipientType, tc.value)
+		if tc.valid {
+			assert.NoError(t, err, "%s %s", tc.recipientType, tc.value)
+		} else {
+			assert.Error(t, err, "%s %s", tc.recipientType, tc.value)
+		}
+	}
+}
+
+func TestRecipientAdoptExisting(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+
+	deleted := []string{}
+	mux.HandleFunc("/recipients", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fmt.Fprint(w, `{"id":"email:2","type":"email","value":"dev@example.com"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"email:1","type":"email","value":"ops@example.com"},{"id":"email:2","type":"email","value":"dev@example.com"}]`)
+	})
+	mux.HandleFunc("/recipients/", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.URL.Path)
+		fmt.Fprint(w, `{"deleted":true}`)
+	})
+	meta := &providerConfig{client: client}
+
+	d := recipientResource().TestResourceData()
+	require.NoError(t, d.Set("type", "email"))
+	require.NoError(t, d.Set("value", "ops@example.com"))
+	require.NoError(t, d.Set("adopt_existing", true))
+	require.NoError(t, recipientCreate(d, meta))
+	assert.Equal(t, "email:1", d.Id())
+	assert.Equal(t, true, d.Get("adopted"))
+
+	require.NoError(t, recipientDelete(d, meta))
+	assert.Empty(t, deleted, "adopted recipients are not deleted")
+
+	d = recipientResource().TestResourceData()
+	require.NoError(t, d.Set("type", "email"))
+	require.NoError(t, d.Set("value", "dev@example.com"))
+	require.NoError(t, recipientCreate(d, meta))
+	assert.Equal(t, "email:2", d.Id())
+	assert.Equal(t, false, d.Get("adopted"))
+
+	require.NoError(t, recipientDelete(d, meta))
+	assert.Equal(t, []string{"/recipients/email:2"}, deleted)
+}
@@ -13,11 +13,10 @@ func statusPageResource() *schema.Resource {
 	return &schema.Resource{
 		Description: "`updown_status_page` defines a status page",
 
-		Create: statusPageCreate,
-		Read:   statusPageRead,
-		Update: statusPageUpdate,
-		Delete: statusPageDelete,
-		Exists: statusPageExists,
+		CreateContext: withRequestContext(statusPageCreate),
+		ReadContext:   withRequestContext(statusPageRead),
+		UpdateContext: withRequestContext(statusPageUpdate),
+		DeleteContext: withRequestContext(statusPageDelete),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -26,12 +25,18 @@ func statusPageResource() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"checks": {
 				Type:        schema.TypeList,
-				Required:    true,
+				Optional:    true,
 				Description: "Ordered list of check tokens to display on the status page.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"ignore_external_checks": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave alone the checks added to the page outside of this resource, such as with `updown_status_page_check`, rather than removing them. They keep their position between the checks of the resource. Enable it before adding such checks, as the ones already read into `checks` are considered managed by the resource.",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -80,18 +85,13 @@ func constructStatusPagePayload(d *schema.ResourceData) updown.StatusPageItem {
 		payload.Visibility = v.(string)
 	}
 
-	checksRaw := d.Get("checks").([]interface{})
-	checks := make([]string, len(checksRaw))
-	for i, v := range checksRaw {
-		checks[i] = v.(string)
-	}
-	payload.Checks = checks
+	payload.Checks = interfaceToStringSlice(d.Get("checks").([]interface{}))
 
 	return payload
 }
 
 func statusPageCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	client := meta.(*providerConfig).client
 
 	page, _, err := client.StatusPage.Add(constructStatusPagePayload(d))
 	if err != nil {
@@ -104,39 +104,120 @@ func statusPageCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func statusPageRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
-	pages, _, err := client.StatusPage.List()
+	p, found, err := findStatusPage(meta.(*providerConfig), d.Id())
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	checks := p.Checks
+	if d.Get("ignore_external_checks").(bool) {
+		checks = managedChecks(checks, interfaceToStringSlice(d.Get("checks").([]interface{})))
+	}
 
+	for k, v := range map[string]interface{}{
+		"name":        p.Name,
+		"description": p.Description,
+		"visibility":  p.Visibility,
+		"access_key":  p.AccessKey,
+		"url":         p.URL,
+		"checks":      checks,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findStatusPage looks for the status page with the given token
+func findStatusPage(config *providerConfig, token string) (updown.StatusPage, bool, error) {
+	pages, err := config.listStatusPages()
 	if err != nil {
-		return fmt.Errorf("reading status pages from the API: %w", err)
+		return updown.StatusPage{}, false, fmt.Errorf("reading status pages from the API: %w", err)
 	}
 
 	for _, p := range pages {
-		if d.Id() == p.Token {
-			for k, v := range map[string]interface{}{
-				"name":        p.Name,
-				"description": p.Description,
-				"visibility":  p.Visibility,
-				"access_key":  p.AccessKey,
-				"url":         p.URL,
-				"checks":      p.Checks,
-			} {
-				if err := d.Set(k, v); err != nil {
-					return err
-				}
-			}
-			return nil
+		if p.Token == token {
+			return p, true, nil
 		}
 	}
 
-	d.SetId("")
-	return nil
+	return updown.StatusPage{}, false, nil
+}
+
+// statusPageItemFromPage returns the payload updating a status page with its current values
+func statusPageItemFromPage(p updown.StatusPage) updown.StatusPageItem {
+	return updown.StatusPageItem{
+		Name:        p.Name,
+		Description: p.Description,
+		Visibility:  p.Visibility,
+		Checks:      append([]string{}, p.Checks...),
+	}
+}
+
+// mergeExternalChecks returns the checks to send when updating a status page from old to
+// checks. The checks which were not managed by the resource keep their position, the managed
+// ones fill the other positions in the configured order.
+func mergeExternalChecks(current, old, checks []string) []string {
+	merged, next := []string{}, 0
+	for _, token := range current {
+		if !containsString(old, token) && !containsString(checks, token) {
+			merged = append(merged, token)
+			continue
+		}
+		if next < len(checks) {
+			merged = append(merged, checks[next])
+			next++
+		}
+	}
+	return append(merged, checks[next:]...)
+}
+
+// managedChecks returns the current checks of a status page which are managed by the resource
+func managedChecks(current, managed []string) []string {
+	checks := []string{}
+	for _, token := range current {
+		if containsString(managed, token) {
+			checks = append(checks, token)
+		}
+	}
+	return checks
+}
+
+func interfaceToStringSlice(l []interface{}) []string {
+	s := make([]string, len(l))
+	for i, v := range l {
+		s[i] = v.(string)
+	}
+	return s
 }
 
 func statusPageUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	config := meta.(*providerConfig)
+
+	config.statusPageLocks.Lock(d.Id())
+	defer config.statusPageLocks.Unlock(d.Id())
+
+	payload := constructStatusPagePayload(d)
+	if d.Get("ignore_external_checks").(bool) {
+		// The external checks must not miss changes made since the snapshot of the list cache
+		config.lists.invalidate()
+		p, found, err := findStatusPage(config, d.Id())
+		if err != nil {
+			return err
+		}
+		if found {
+			old, _ := d.GetChange("checks")
+			payload.Checks = mergeExternalChecks(p.Checks, interfaceToStringSlice(old.([]interface{})), payload.Checks)
+		}
+	}
 
-	_, _, err := client.StatusPage.Update(d.Id(), constructStatusPagePayload(d))
+	_, _, err := config.client.StatusPage.Update(d.Id(), payload)
 	if err != nil {
 		return fmt.Errorf("updating status page with the API: %w", err)
 	}
@@ -145,7 +226,7 @@ func statusPageUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func statusPageDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*updown.Client)
+	client := meta.(*providerConfig).client
 	deleted, _, err := client.StatusPage.Remove(d.Id())
 
 	if err != nil {
@@ -158,8 +239,3 @@ func statusPageDelete(d *schema.ResourceData, meta interface{}) error {
 
 	return nil
 }
-
-func statusPageExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	err := statusPageRead(d, meta)
-	return err == nil, err
-}
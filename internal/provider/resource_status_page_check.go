@@ -0,0 +1,201 @@
+// Package provider implements the Terraform provider for updown.io.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func statusPageCheckResource() *schema.Resource {
+	return &schema.Resource{
+		Description: "`updown_status_page_check` adds a check to a status page, independently of the resource managing the page. " +
+			"Set `ignore_external_checks` on the `updown_status_page` so that it does not remove the checks added this way.",
+
+		CreateContext: withRequestContext(statusPageCheckCreate),
+		ReadContext:   withRequestContext(statusPageCheckRead),
+		UpdateContext: withRequestContext(statusPageCheckUpdate),
+		DeleteContext: withRequestContext(statusPageCheckDelete),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: statusPageCheckImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"status_page_token": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Token of the status page.",
+			},
+			"check_token": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Token of the check to display on the status page.",
+			},
+			"position": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Position of the check on the status page, starting at 1. The check is added at the end of the page when not set.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+		},
+	}
+}
+
+// statusPageCheckID returns the ID of the association between a status page and a check
+func statusPageCheckID(pageToken, checkToken string) string {
+	return pageToken + "/" + checkToken
+}
+
+// parseStatusPageCheckID splits the ID of the association between a status page and a check
+func parseStatusPageCheckID(id string) (pageToken, checkToken string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <status_page_token>/<check_token>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// placeCheck moves, or adds, token to the given position of checks, starting at 1. Without
+// position, checks already holding token are left unchanged and the others get it at the end.
+func placeCheck(checks []string, token string, position int) []string {
+	if position == 0 {
+		if containsString(checks, token) {
+			return checks
+		}
+		return append(checks, token)
+	}
+
+	placed := removeCheck(checks, token)
+	i := position - 1
+	if i > len(placed) {
+		i = len(placed)
+	}
+	return append(placed[:i], append([]string{token}, placed[i:]...)...)
+}
+
+// removeCheck returns checks without token
+func removeCheck(checks []string, token string) []string {
+	removed := []string{}
+	for _, t := range checks {
+		if t != token {
+			removed = append(removed, t)
+		}
+	}
+	return removed
+}
+
+// updateStatusPageChecks applies update to the checks of a status page, and tells if the page
+// was found
+func updateStatusPageChecks(meta interface{}, pageToken string, update func([]string) []string) (bool, error) {
+	config := meta.(*providerConfig)
+
+	config.statusPageLocks.Lock(pageToken)
+	defer config.statusPageLocks.Unlock(pageToken)
+
+	// The payload is built from the listed page, which must not miss changes made since the
+	// snapshot of the list cache
+	config.lists.invalidate()
+	p, found, err := findStatusPage(config, pageToken)
+	if err != nil || !found {
+		return found, err
+	}
+
+	payload := statusPageItemFromPage(p)
+	payload.Checks = update(payload.Checks)
+	if _, _, err := config.client.StatusPage.Update(pageToken, payload); err != nil {
+		return true, fmt.Errorf("updating status page with the API: %w", err)
+	}
+
+	return true, nil
+}
+
+// placeStatusPageCheck moves, or adds, the check at the configured position of the status page
+func placeStatusPageCheck(d *schema.ResourceData, meta interface{}) error {
+	pageToken, checkToken := d.Get("status_page_token").(string), d.Get("check_token").(string)
+	position := d.Get("position").(int)
+
+	found, err := updateStatusPageChecks(meta, pageToken, func(checks []string) []string {
+		return placeCheck(checks, checkToken, position)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("status page %s not found", pageToken)
+	}
+
+	return nil
+}
+
+func statusPageCheckCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := placeStatusPageCheck(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(statusPageCheckID(d.Get("status_page_token").(string), d.Get("check_token").(string)))
+
+	return statusPageCheckRead(d, meta)
+}
+
+func statusPageCheckRead(d *schema.ResourceData, meta interface{}) error {
+	pageToken, checkToken, err := parseStatusPageCheckID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	p, found, err := findStatusPage(meta.(*providerConfig), pageToken)
+	if err != nil {
+		return err
+	}
+
+	position := 0
+	for i, t := range p.Checks {
+		if t == checkToken {
+			position = i + 1
+			break
+		}
+	}
+	if !found || position == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	for k, v := range map[string]interface{}{
+		"status_page_token": pageToken,
+		"check_token":       checkToken,
+		"position":          position,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func statusPageCheckUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := placeStatusPageCheck(d, meta); err != nil {
+		return err
+	}
+
+	return statusPageCheckRead(d, meta)
+}
+
+func statusPageCheckDelete(d *schema.ResourceData, meta interface{}) error {
+	pageToken, checkToken, err := parseStatusPageCheckID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// Nothing to do when the status page was deleted
+	_, err = updateStatusPageChecks(meta, pageToken, func(checks []string) []string {
+		return removeCheck(checks, checkToken)
+	})
+	return err
+}
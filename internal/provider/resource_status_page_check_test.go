@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handleStatusPage serves a status page whose checks can be updated, and returns a function
+// giving its current checks
+func handleStatusPage(t *testing.T, mux *http.ServeMux, checks []string) func() []string {
+	var mu sync.Mutex
+	page := func() updown.StatusPage {
+		return updown.StatusPage{Token: "pppp", Name: "Company", Visibility: "public", Checks: checks}
+	}
+
+	mux.HandleFunc("/status_pages", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		require.NoError(t, json.NewEncoder(w).Encode([]updown.StatusPage{page()}))
+	})
+	mux.HandleFunc("/status_pages/pppp", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var item updown.StatusPageItem
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+		assert.Equal(t, "Company", item.Name)
+		checks = item.Checks
+		require.NoError(t, json.NewEncoder(w).Encode(page()))
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return checks
+	}
+}
+
+func TestStatusPageCheck(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	checks := handleStatusPage(t, mux, []string{"aaaa", "bbbb"})
+	meta := &providerConfig{client: client, statusPageLocks: newMutexKV()}
+
+	d := schema.TestResourceDataRaw(t, statusPageCheckResource().Schema, map[string]interface{}{
+		"status_page_token": "pppp",
+		"check_token":       "cccc",
+	})
+	require.NoError(t, statusPageCheckCreate(d, meta))
+	assert.Equal(t, "pppp/cccc", d.Id())
+	assert.Equal(t, 3, d.Get("position"))
+	assert.Equal(t, []string{"aaaa", "bbbb", "cccc"}, checks())
+
+	d = schema.TestResourceDataRaw(t, statusPageCheckResource().Schema, map[string]interface{}{
+		"status_page_token": "pppp",
+		"check_token":       "dddd",
+		"position":          1,
+	})
+	require.NoError(t, statusPageCheckCreate(d, meta))
+	assert.Equal(t, []string{"dddd", "aaaa", "bbbb", "cccc"}, checks())
+
+	require.NoError(t, d.Set("position", 3))
+	require.NoError(t, statusPageCheckUpdate(d, meta))
+	assert.Equal(t, []string{"aaaa", "bbbb", "dddd", "cccc"}, checks())
+
+	require.NoError(t, statusPageCheckDelete(d, meta))
+	assert.Equal(t, []string{"aaaa", "bbbb", "cccc"}, checks())
+
+	require.NoError(t, statusPageCheckRead(d, meta))
+	assert.Empty(t, d.Id())
+
+	d.SetId("missing/aaaa")
+	require.NoError(t, statusPageCheckRead(d, meta))
+	assert.Empty(t, d.Id())
+}
+
+func TestStatusPageCheck_ExternalChange(t *testing.T) {
+	mux, client, teardown := setupClient()
+	defer teardown()
+	checks := handleStatusPage(t, mux, []string{"aaaa", "bbbb"})
+	meta := &providerConfig{client: client, statusPageLocks: newMutexKV(), lists: newListCache(time.Minute)}
+
+	// The list cache has a snapshot of the page before a check is added outside of Terraform
+	_, err := meta.listStatusPages()
+	require.NoError(t, err)
+	_, _, err = client.StatusPage.Update("pppp", updown.StatusPageItem{Name: "Company", Checks: []string{"aaaa", "bbbb", "eeee"}})
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, statusPageCheckResource().Schema, map[string]interface{}{
+		"status_page_token": "pppp",
+		"check_token":       "cccc",
+	})
+	require.NoError(t, statusPageCheckCreate(d, meta))
+	assert.Equal(t, []string{"aaaa", "bbbb", "eeee", "cccc"}, checks())
+}
+
+func TestPlaceCheck(t *testing.T) {
+	assert.Equal(t, []string{"aaaa", "bbbb"}, placeCheck([]string{"aaaa", "bbbb"}, "bbbb", 0))
+	assert.Equal(t, []string{"aaaa", "bbbb"}, placeCheck([]string{"aaaa"}, "bbbb", 0))
+	assert.Equal(t, []string{"bbbb", "aaaa"}, placeCheck([]string{"aaaa", "bbbb"}, "bbbb", 1))
+	assert.Equal(t, []string{"aaaa", "bbbb"}, placeCheck([]string{"aaaa"}, "bbbb", 10))
+}
+
+func TestParseStatusPageCheckID(t *testing.T) {
+	pageToken, checkToken, err := parseStatusPageCheckID("pppp/aaaa")
+	require.NoError(t, err)
+	assert.Equal(t, "pppp", pageToken)
+	assert.Equal(t, "aaaa", checkToken)
+
+	for _, id := range []string{"pppp", "pppp/", "/aaaa", "pppp/aaaa/bbbb"} {
+		_, _, err := parseStatusPageCheckID(id)
+		assert.Error(t, err, id)
+	}
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeExternalChecks(t *testing.T) {
+	// cccc was added outside of the resource, which now manages bbbb, aaaa and dddd
+	assert.Equal(t,
+		[]string{"bbbb", "cccc", "aaaa", "dddd"},
+		mergeExternalChecks([]string{"aaaa", "cccc", "bbbb"}, []string{"aaaa", "bbbb"}, []string{"bbbb", "aaaa", "dddd"}),
+	)
+	assert.Equal(t,
+		[]string{"bbbb", "cccc"},
+		mergeExternalChecks([]string{"aaaa", "cccc", "bbbb"}, []string{"aaaa", "bbbb"}, []string{"bbbb"}),
+	)
+	assert.Equal(t, []string{"cccc"}, mergeExternalChecks([]string{"cccc"}, nil, nil))
+}
+
+func TestManagedChecks(t *testing.T) {
+	assert.Equal(t, []string{"bbbb", "aaaa"}, managedChecks([]string{"bbbb", "cccc", "aaaa"}, []string{"aaaa", "bbbb"}))
+	assert.Equal(t, []string{}, managedChecks([]string{"cccc"}, nil))
+}
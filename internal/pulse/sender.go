@@ -0,0 +1,170 @@
+// Package pulse sends heartbeats to the pulse checks of updown.io, for scheduled jobs and
+// cron tasks, without ever revealing the secret part of their URL.
+package pulse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// Sender sends the heartbeats of a pulse check. Its settings must not be changed while it
+// sends heartbeats.
+type Sender struct {
+	URL *URL
+
+	// Client sends the heartbeats, http.DefaultClient by default
+	Client *http.Client
+	// Timeout of each attempt at sending a heartbeat, 10s by default
+	Timeout time.Duration
+	// Maximum number of attempts at sending a heartbeat, 5 by default. Network errors, 429 and
+	// 5xx responses are retried.
+	MaxAttempts int
+	// Wait before the first retry, doubling on every attempt, 1s by default
+	InitialWait time.Duration
+	// Maximum wait between attempts, 30s by default
+	MaxWait time.Duration
+
+	// SignalStart makes Run send a heartbeat when the job starts, besides the one sent when it
+	// succeeds. updown.io has a single kind of heartbeat, so this only lets jobs running longer
+	// than the period of the pulse check report in before they end.
+	SignalStart bool
+	// SignalFailure makes Run send a heartbeat when the job fails too, to only monitor that it
+	// runs
+	SignalFailure bool
+}
+
+// NewSender returns a Sender for a pulse URL with the default settings
+func NewSender(pulseURL string) (*Sender, error) {
+	u, err := ParseURL(pulseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{URL: u}, nil
+}
+
+func (s *Sender) settings() (client *http.Client, timeout time.Duration, attempts int, wait, maxWait time.Duration) {
+	client, timeout, attempts, wait, maxWait = s.Client, s.Timeout, s.MaxAttempts, s.InitialWait, s.MaxWait
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if attempts <= 0 {
+		attempts = 5
+	}
+	if wait <= 0 {
+		wait = time.Second
+	}
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	return
+}
+
+// Send sends a heartbeat, retrying failures with an exponential backoff until it succeeds,
+// the attempts are exhausted or ctx is done
+func (s *Sender) Send(ctx context.Context) error {
+	client, timeout, attempts, wait, maxWait := s.settings()
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.send(ctx, client, timeout)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts || retryAfter < 0 {
+			return fmt.Errorf("sending heartbeat to %s: %w", s.URL, err)
+		}
+
+		log.Printf("[WARN] sending heartbeat to %s failed, attempt %d/%d: %s", s.URL, attempt, attempts, err)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > maxWait {
+			wait = maxWait
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("sending heartbeat to %s: %w", s.URL, ctx.Err())
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// send sends a heartbeat once. On failure, it returns the wait requested by the server before
+// retrying, 0 when it did not request one, or -1 when the failure is not worth retrying.
+func (s *Sender) send(ctx context.Context, client *http.Client, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL.Secret(), nil)
+	if err != nil {
+		return -1, errors.New("invalid url")
+	}
+	req.Header.Set("User-Agent", "updown-pulse")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The errors of the client hold the URL, secret included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, errors.New(updown.RedactString(err.Error()))
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	if secs, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && secs > 0 {
+		return time.Duration(secs) * time.Second, err
+	}
+	return 0, err
+}
+
+// Run runs a job and sends a heartbeat when it succeeds, as well as when it starts and fails
+// according to SignalStart and SignalFailure. It returns the error of the job, or else the one
+// of the final heartbeat. A failure to send the start heartbeat is only logged, so that it
+// never prevents the job from running.
+func (s *Sender) Run(ctx context.Context, job func(context.Context) error) error {
+	if s.SignalStart {
+		if err := s.Send(ctx); err != nil {
+			log.Printf("[WARN] %s", err)
+		}
+	}
+
+	jobErr := job(ctx)
+	if jobErr != nil && !s.SignalFailure {
+		return jobErr
+	}
+
+	if err := s.Send(ctx); err != nil {
+		if jobErr != nil {
+			log.Printf("[WARN] %s", err)
+			return jobErr
+		}
+		return err
+	}
+	return jobErr
+}
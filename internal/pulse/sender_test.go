@@ -0,0 +1,114 @@
+package pulse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setup returns a Sender whose heartbeats to https://pulse.updown.io/abcd/s3cr3t are served
+// by handler, and the output of its logs
+func setup(t *testing.T, handler http.HandlerFunc) (*Sender, *bytes.Buffer) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	sender, err := NewSender("http://pulse.updown.io/abcd/s3cr3t")
+	require.NoError(t, err)
+	sender.Client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+	sender.InitialWait = time.Millisecond
+
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	return sender, &output
+}
+
+func TestSender_Send(t *testing.T) {
+	var calls int32
+	sender, output := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/abcd/s3cr3t", r.URL.Path)
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	require.NoError(t, sender.Send(context.Background()))
+	assert.Equal(t, int32(3), calls)
+	assert.Contains(t, output.String(), "sending heartbeat to http://pulse.updown.io/abcd/<redacted> failed, attempt 2/5: 503 Service Unavailable")
+	assert.NotContains(t, output.String(), "s3cr3t")
+}
+
+func TestSender_Send_Errors(t *testing.T) {
+	var calls int32
+	sender, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	err := sender.Send(context.Background())
+	assert.EqualError(t, err, "sending heartbeat to http://pulse.updown.io/abcd/<redacted>: 404 Not Found")
+	assert.Equal(t, int32(1), calls)
+
+	// Timeouts are retried, and their errors do not hold the URL either
+	sender, output := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	sender.Timeout = 10 * time.Millisecond
+	sender.MaxAttempts = 2
+
+	err = sender.Send(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deadline exceeded")
+	assert.NotContains(t, err.Error(), "s3cr3t")
+	assert.NotContains(t, output.String(), "s3cr3t")
+}
+
+func TestSender_Run(t *testing.T) {
+	var calls int32
+	sender, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+	jobErr := errors.New("job failed")
+
+	for name, tc := range map[string]struct {
+		signalStart   bool
+		signalFailure bool
+		err           error
+		calls         int32
+	}{
+		"success":                {calls: 1},
+		"failure":                {err: jobErr, calls: 0},
+		"start and success":      {signalStart: true, calls: 2},
+		"start and failure":      {signalStart: true, err: jobErr, calls: 1},
+		"failure signalled":      {signalFailure: true, err: jobErr, calls: 1},
+		"start, failure signals": {signalStart: true, signalFailure: true, err: jobErr, calls: 2},
+	} {
+		atomic.StoreInt32(&calls, 0)
+		sender.SignalStart = tc.signalStart
+		sender.SignalFailure = tc.signalFailure
+
+		err := sender.Run(context.Background(), func(context.Context) error {
+			return tc.err
+		})
+		assert.Equal(t, tc.err, err, name)
+		assert.Equal(t, tc.calls, atomic.LoadInt32(&calls), name)
+	}
+}
@@ -0,0 +1,84 @@
+// Package pulse sends heartbeats to the pulse checks of updown.io, for scheduled jobs and
+// cron tasks, without ever revealing the secret part of their URL.
+package pulse
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// URL is a parsed pulse URL, https://pulse.updown.io/<token>/<secret>. It formats with its
+// secret redacted, so that it can be logged safely.
+type URL struct {
+	// Token of the pulse check
+	Token string
+
+	u *url.URL
+}
+
+// ParseURL parses and validates a pulse URL, as set in the pulse_url attribute of the
+// updown_pulse resource. Its errors never contain the URL, which may hold a secret.
+func ParseURL(raw string) (*URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, errors.New("invalid pulse url")
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, errors.New("invalid pulse url: scheme must be https or http")
+	}
+	if !strings.HasPrefix(u.Hostname(), "pulse.") {
+		return nil, errors.New("invalid pulse url: host must be a pulse host, such as pulse.updown.io")
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, errors.New("invalid pulse url: unexpected credentials, query or fragment")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.New("invalid pulse url: path must be /<token>/<secret>")
+	}
+	if parts[1] == updown.Redacted || parts[1] == url.PathEscape(updown.Redacted) {
+		return nil, errors.New("invalid pulse url: its secret is redacted, as returned by the API on reads")
+	}
+
+	return &URL{Token: parts[0], u: u}, nil
+}
+
+// String returns the URL with its secret redacted
+func (p *URL) String() string {
+	return updown.RedactURL(p.u)
+}
+
+// GoString returns the URL with its secret redacted, for the %#v verb
+func (p *URL) GoString() string {
+	return fmt.Sprintf("pulse.URL{Token:%q, URL:%q}", p.Token, p.String())
+}
+
+// Secret returns the full URL, secret included, to send heartbeats to
+func (p *URL) Secret() string {
+	return p.u.String()
+}
+
+// ResolveURL reads the URL of a pulse check from the API. The API may redact the secret of the
+// URL on reads, in which case the URL cannot be resolved and must be set from the pulse_url
+// attribute of the updown_pulse resource instead.
+func ResolveURL(client *updown.Client, token string) (*URL, error) {
+	check, _, err := client.Check.Get(token)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulse check %s: %w", token, err)
+	}
+	if check.Type != "pulse" {
+		return nil, fmt.Errorf("check %s is not a pulse check (type: %s)", token, check.Type)
+	}
+
+	u, err := ParseURL(check.URL)
+	if err != nil {
+		return nil, fmt.Errorf("pulse check %s: %w", token, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,63 @@
+package pulse
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	u, err := ParseURL(" https://pulse.updown.io/abcd/s3cr3t\n")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", u.Token)
+	assert.Equal(t, "https://pulse.updown.io/abcd/s3cr3t", u.Secret())
+	assert.Equal(t, "https://pulse.updown.io/abcd/<redacted>", u.String())
+	assert.NotContains(t, fmt.Sprintf("%v %s %#v", u, u, u), "s3cr3t")
+
+	for raw, msg := range map[string]string{
+		"ftp://pulse.updown.io/abcd/s3cr3t":       "invalid pulse url: scheme must be https or http",
+		"https://updown.io/abcd/s3cr3t":           "invalid pulse url: host must be a pulse host, such as pulse.updown.io",
+		"https://pulse.updown.io/abcd":            "invalid pulse url: path must be /<token>/<secret>",
+		"https://pulse.updown.io/abcd/s3cr3t/x":   "invalid pulse url: path must be /<token>/<secret>",
+		"https://pulse.updown.io/abcd/s3cr3t?x=1": "invalid pulse url: unexpected credentials, query or fragment",
+		"https://pulse.updown.io/abcd/<redacted>": "invalid pulse url: its secret is redacted, as returned by the API on reads",
+		"https://pulse.updown.io/abcd/s3cr3t\x7f": "invalid pulse url",
+	} {
+		_, err := ParseURL(raw)
+		assert.EqualError(t, err, msg, raw)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/checks/abcd":
+			fmt.Fprint(w, `{"token":"abcd","type":"pulse","url":"https://pulse.updown.io/abcd/s3cr3t"}`)
+		case "/api/checks/efgh":
+			fmt.Fprint(w, `{"token":"efgh","type":"pulse","url":"https://pulse.updown.io/efgh/<redacted>"}`)
+		default:
+			fmt.Fprint(w, `{"token":"ijkl","type":"http","url":"https://example.com"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := updown.NewClient("api-key", nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	u, err := ResolveURL(client, "abcd")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pulse.updown.io/abcd/s3cr3t", u.Secret())
+
+	_, err = ResolveURL(client, "efgh")
+	assert.EqualError(t, err, "pulse check efgh: invalid pulse url: its secret is redacted, as returned by the API on reads")
+
+	_, err = ResolveURL(client, "ijkl")
+	assert.EqualError(t, err, "check ijkl is not a pulse check (type: http)")
+}
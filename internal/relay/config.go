@@ -0,0 +1,143 @@
+// Package relay forwards the events of updown.io webhooks to Slack, Microsoft Teams or any
+// HTTP endpoint, formatted with user supplied templates.
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Types of targets
+const (
+	TargetSlack   = "slack"
+	TargetTeams   = "teams"
+	TargetGeneric = "generic"
+)
+
+// Config is the configuration of a Relay, usually read from a JSON file with LoadConfig
+type Config struct {
+	// Targets by name
+	Targets map[string]TargetConfig `json:"targets"`
+	// Routes of the events to the targets. An event is delivered once to every target of every
+	// route it matches.
+	Routes []RouteConfig `json:"routes"`
+	Retry  RetryConfig   `json:"retry"`
+	// Number of deliveries which can wait to be sent, 1000 by default. The webhook requests are
+	// rejected with a 503 status when it is full, so that updown.io sends them again.
+	QueueSize int `json:"queue_size"`
+	// Number of deliveries sent concurrently, 4 by default
+	Workers int `json:"workers"`
+}
+
+// TargetConfig configures a destination of the events
+type TargetConfig struct {
+	// Type of target: slack, teams or generic
+	Type string `json:"type"`
+	// URL the events are posted to. Env variables such as ${SLACK_WEBHOOK_URL} are expanded.
+	URL string `json:"url"`
+	// Go template of the message text for slack and teams targets, or of the request body for
+	// generic targets, executed with an updown.Event. Generic targets send the event as JSON
+	// when it is not set.
+	Template string `json:"template"`
+	// File to read Template from
+	TemplateFile string `json:"template_file"`
+	// Content-Type of the requests of generic targets, application/json by default
+	ContentType string `json:"content_type"`
+	// Headers added to the requests. Env variables in their values are expanded.
+	Headers map[string]string `json:"headers"`
+}
+
+// RouteConfig selects events and the targets to deliver them to. Unset criteria match every
+// event, so a route with only targets is a catch-all.
+type RouteConfig struct {
+	// Event types, such as check.down
+	Events []string `json:"events"`
+	// Alias and URL patterns of the checks, where * matches any characters and ? any single
+	// one, such as billing-* or https://*.example.com/*
+	Aliases []string `json:"aliases"`
+	URLs    []string `json:"urls"`
+	// Names of the targets
+	Targets []string `json:"targets"`
+}
+
+// RetryConfig configures the retries of failed deliveries: network errors, 429 and 5xx
+// responses
+type RetryConfig struct {
+	// Maximum number of attempts of a delivery, 5 by default
+	MaxAttempts int `json:"max_attempts"`
+	// Wait before the first retry, doubling on every attempt, 1s by default
+	InitialWait Duration `json:"initial_wait"`
+	// Maximum wait between attempts, 1m by default
+	MaxWait Duration `json:"max_wait"`
+}
+
+// Duration is a time.Duration written as a string such as 30s in JSON
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as 30s: %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadConfig reads a configuration from a JSON file
+func LoadConfig(file string) (Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// validate checks the routes and the targets of a configuration
+func (c Config) validate() error {
+	if len(c.Targets) == 0 {
+		return fmt.Errorf("no target is configured")
+	}
+
+	for name, t := range c.Targets {
+		switch t.Type {
+		case TargetSlack, TargetTeams, TargetGeneric:
+		default:
+			return fmt.Errorf("target %s: unknown type %q, expected slack, teams or generic", name, t.Type)
+		}
+		if t.URL == "" {
+			return fmt.Errorf("target %s: url is required", name)
+		}
+		if t.Template != "" && t.TemplateFile != "" {
+			return fmt.Errorf("target %s: template and template_file are mutually exclusive", name)
+		}
+	}
+
+	// Without routes, every event would be dropped
+	if len(c.Routes) == 0 {
+		return fmt.Errorf("no route is configured")
+	}
+	for i, r := range c.Routes {
+		if len(r.Targets) == 0 {
+			return fmt.Errorf("route %d: targets are required", i)
+		}
+		for _, name := range r.Targets {
+			if _, ok := c.Targets[name]; !ok {
+				return fmt.Errorf("route %d: unknown target %q", i, name)
+			}
+		}
+	}
+
+	return nil
+}
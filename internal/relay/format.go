@@ -0,0 +1,131 @@
+// Package relay forwards the events of updown.io webhooks to Slack, Microsoft Teams or any
+// HTTP endpoint, formatted with user supplied templates.
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// defaultMessage is the template of the messages of slack and teams targets which do not set one
+const defaultMessage = `[{{ .Type }}] {{ checkName .Check }}{{ with .Description }}: {{ . }}{{ end }}`
+
+// templateFuncs are the functions available to the templates, besides the text/template ones
+var templateFuncs = template.FuncMap{
+	"checkName": checkName,
+	// json encodes a value, such as a string to embed in a JSON body
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"redact": updown.RedactString,
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+}
+
+// checkName returns the alias of a check, or its URL without secret
+func checkName(c updown.Check) string {
+	if c.Alias != "" {
+		return c.Alias
+	}
+	return updown.RedactString(c.URL)
+}
+
+// target is a destination of the events
+type target struct {
+	name        string
+	kind        string
+	url         string
+	contentType string
+	headers     map[string]string
+	tmpl        *template.Template
+}
+
+// newTarget parses the template of a target and expands the env variables of its settings
+func newTarget(name string, cfg TargetConfig) (*target, error) {
+	t := &target{
+		name:        name,
+		kind:        cfg.Type,
+		url:         os.ExpandEnv(cfg.URL),
+		contentType: cfg.ContentType,
+		headers:     map[string]string{},
+	}
+	if t.url == "" {
+		return nil, fmt.Errorf("target %s: url %q expands to an empty string", name, cfg.URL)
+	}
+	for k, v := range cfg.Headers {
+		t.headers[k] = os.ExpandEnv(v)
+	}
+	if t.contentType == "" || t.kind != TargetGeneric {
+		t.contentType = "application/json"
+	}
+
+	text := cfg.Template
+	if cfg.TemplateFile != "" {
+		data, err := os.ReadFile(cfg.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", name, err)
+		}
+		text = string(data)
+	}
+	if text == "" && t.kind != TargetGeneric {
+		text = defaultMessage
+	}
+	if text != "" {
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("target %s: parsing template: %w", name, err)
+		}
+		t.tmpl = tmpl
+	}
+
+	return t, nil
+}
+
+// body returns the body of the request delivering an event to the target
+func (t *target) body(e updown.Event) ([]byte, error) {
+	if t.tmpl == nil {
+		if len(e.Raw) == 0 {
+			return json.Marshal(e)
+		}
+		return e.Raw, nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+
+	switch t.kind {
+	case TargetSlack:
+		return json.Marshal(map[string]string{"text": buf.String()})
+	case TargetTeams:
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    e.Type + " " + checkName(e.Check),
+			"themeColor": themeColor(e.Type),
+			"text":       buf.String(),
+		})
+	default:
+		return buf.Bytes(), nil
+	}
+}
+
+// themeColor returns the color of the Teams cards of an event type
+func themeColor(eventType string) string {
+	switch eventType {
+	case updown.EventCheckDown, updown.EventCheckSSLInvalid:
+		return "D7263D"
+	case updown.EventCheckUp, updown.EventCheckSSLValid, updown.EventCheckSSLRenewed:
+		return "2EB67D"
+	default:
+		return "ECB22E"
+	}
+}
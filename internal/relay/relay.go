@@ -0,0 +1,329 @@
+// Package relay forwards the events of updown.io webhooks to Slack, Microsoft Teams or any
+// HTTP endpoint, formatted with user supplied templates.
+package relay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// ErrQueueFull is returned when an event cannot be queued for delivery
+var ErrQueueFull = errors.New("delivery queue is full")
+
+// route selects the events delivered to some targets
+type route struct {
+	events  map[string]bool
+	aliases []*regexp.Regexp
+	urls    []*regexp.Regexp
+	targets []string
+}
+
+// delivery is an event to send to a target
+type delivery struct {
+	target *target
+	event  updown.Event
+}
+
+// Relay forwards the events of updown.io webhooks to the targets of the routes they match.
+// Events are queued for delivery by its Handler, and sent by Run.
+type Relay struct {
+	targets map[string]*target
+	routes  []route
+	retry   RetryConfig
+	workers int
+
+	queue     chan delivery
+	enqueueMu sync.Mutex
+
+	// Client sends the deliveries, with a 30s timeout by default
+	Client *http.Client
+
+	// Secret, when set, must be given by the webhooks in the token query parameter of their URL,
+	// or in the X-Webhook-Secret header
+	Secret string
+}
+
+// globToRegexp compiles a pattern where * matches any characters and ? any single one
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	for _, p := range patterns {
+		re, err := globToRegexp(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// New returns a Relay for a configuration
+func New(cfg Config) (*Relay, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Relay{
+		targets: map[string]*target{},
+		retry:   cfg.Retry,
+		workers: cfg.Workers,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	if r.retry.MaxAttempts <= 0 {
+		r.retry.MaxAttempts = 5
+	}
+	if r.retry.InitialWait <= 0 {
+		r.retry.InitialWait = Duration(time.Second)
+	}
+	if r.retry.MaxWait <= 0 {
+		r.retry.MaxWait = Duration(time.Minute)
+	}
+	if r.workers <= 0 {
+		r.workers = 4
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	r.queue = make(chan delivery, queueSize)
+
+	for name, tc := range cfg.Targets {
+		t, err := newTarget(name, tc)
+		if err != nil {
+			return nil, err
+		}
+		r.targets[name] = t
+	}
+
+	for i, rc := range cfg.Routes {
+		rt := route{events: map[string]bool{}, targets: rc.Targets}
+		for _, e := range rc.Events {
+			rt.events[e] = true
+		}
+		var err error
+		if rt.aliases, err = compileGlobs(rc.Aliases); err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		if rt.urls, err = compileGlobs(rc.URLs); err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		r.routes = append(r.routes, rt)
+	}
+
+	return r, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches tells if a route selects an event
+func (rt route) matches(e updown.Event) bool {
+	if len(rt.events) > 0 && !rt.events[e.Type] {
+		return false
+	}
+	return matchesAny(rt.aliases, e.Check.Alias) && matchesAny(rt.urls, e.Check.URL)
+}
+
+// Targets returns the targets an event is delivered to, once each, in the order of the routes
+func (r *Relay) Targets(e updown.Event) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, rt := range r.routes {
+		if !rt.matches(e) {
+			continue
+		}
+		for _, name := range rt.targets {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Enqueue queues the deliveries of an event to its targets. It fails with ErrQueueFull when
+// the queue cannot hold them.
+func (r *Relay) Enqueue(e updown.Event) error {
+	return r.EnqueueAll([]updown.Event{e})
+}
+
+// EnqueueAll queues the deliveries of events to their targets, all of them or none. It fails
+// with ErrQueueFull when the queue cannot hold them, so that the events can be sent again
+// without being delivered twice.
+func (r *Relay) EnqueueAll(events []updown.Event) error {
+	deliveries := []delivery{}
+	for _, e := range events {
+		for _, name := range r.Targets(e) {
+			deliveries = append(deliveries, delivery{target: r.targets[name], event: e})
+		}
+	}
+
+	// The queue is only filled here, the room left can only grow until the deliveries are queued
+	r.enqueueMu.Lock()
+	defer r.enqueueMu.Unlock()
+	if len(deliveries) > cap(r.queue)-len(r.queue) {
+		return ErrQueueFull
+	}
+	for _, d := range deliveries {
+		r.queue <- d
+	}
+	return nil
+}
+
+// Handler returns the http.Handler receiving the updown.io webhooks. Requests are answered
+// once their events are queued, not delivered. When Secret is set, the requests must carry it.
+func (r *Relay) Handler() http.Handler {
+	h := &updown.WebhookHandler{
+		OnEvents: func(_ context.Context, events []updown.Event) error {
+			return r.EnqueueAll(events)
+		},
+		Secret: r.Secret,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Let updown.io send the events again later, rather than losing them
+		if len(r.queue) == cap(r.queue) {
+			http.Error(w, ErrQueueFull.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// Run sends the queued deliveries until ctx is done, which cancels the deliveries in progress.
+// The deliveries still queued then are lost.
+func (r *Relay) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-r.queue:
+					if err := r.deliver(ctx, d); err != nil {
+						log.Printf("[ERROR] delivering %s of check %s to %s: %s", d.event.Type, d.event.Check.Token, d.target.name, err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver sends an event to a target, retrying network errors, 429 and 5xx responses
+func (r *Relay) deliver(ctx context.Context, d delivery) error {
+	body, err := d.target.body(d.event)
+	if err != nil {
+		return err
+	}
+
+	wait := time.Duration(r.retry.InitialWait)
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := r.send(ctx, d.target, body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= r.retry.MaxAttempts || retryAfter < 0 {
+			return err
+		}
+
+		log.Printf("[WARN] delivering %s to %s failed, attempt %d/%d: %s", d.event.Type, d.target.name, attempt, r.retry.MaxAttempts, err)
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > time.Duration(r.retry.MaxWait) {
+			wait = time.Duration(r.retry.MaxWait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// send posts a body to a target once. On failure, it returns the wait requested by the target
+// before retrying, 0 when it did not request one, or -1 when the failure is not worth retrying.
+func (r *Relay) send(ctx context.Context, t *target, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return -1, errors.New("invalid url")
+	}
+	req.Header.Set("Content-Type", t.contentType)
+	req.Header.Set("User-Agent", "updown-relay")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		// The URL of the target, such as a Slack webhook, holds a secret
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s > 0 {
+		return time.Duration(s) * time.Second, err
+	}
+	return 0, err
+}
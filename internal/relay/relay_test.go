@@ -0,0 +1,255 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a target endpoint recording the requests it receives
+type receiver struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	failures int
+	received chan struct{}
+}
+
+func newReceiver(t *testing.T, failures int) (*receiver, string) {
+	rc := &receiver{failures: failures, received: make(chan struct{}, 100)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if rc.failures > 0 {
+			rc.failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rc.bodies = append(rc.bodies, string(body))
+		rc.headers = append(rc.headers, r.Header)
+		rc.received <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+	return rc, server.URL
+}
+
+func (rc *receiver) wait(t *testing.T, n int) []string {
+	for i := 0; i < n; i++ {
+		select {
+		case <-rc.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d deliveries, expected %d", i, n)
+		}
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]string{}, rc.bodies...)
+}
+
+func post(t *testing.T, h http.Handler, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRelay(t *testing.T) {
+	slack, slackURL := newReceiver(t, 0)
+	teams, teamsURL := newReceiver(t, 2)
+	generic, genericURL := newReceiver(t, 0)
+	os.Setenv("RELAY_TEST_TOKEN", "s3cr3t")
+	defer os.Unsetenv("RELAY_TEST_TOKEN")
+
+	r, err := New(Config{
+		Targets: map[string]TargetConfig{
+			"slack": {Type: TargetSlack, URL: slackURL},
+			"teams": {Type: TargetTeams, URL: teamsURL, Template: `{{ upper .Type }} on {{ checkName .Check }}`},
+			"pager": {
+				Type:     TargetGeneric,
+				URL:      genericURL,
+				Template: `{"summary":{{ json .Description }},"check":{{ json .Check.Token }}}`,
+				Headers:  map[string]string{"Authorization": "Bearer ${RELAY_TEST_TOKEN}"},
+			},
+		},
+		Routes: []RouteConfig{
+			{Aliases: []string{"billing-*"}, Targets: []string{"slack", "teams"}},
+			{Events: []string{updown.EventCheckDown}, URLs: []string{"https://*.example.com/*"}, Targets: []string{"pager", "slack"}},
+		},
+		Retry: RetryConfig{InitialWait: Duration(time.Millisecond)},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	code := post(t, r.Handler(), `[
+		{"event":"check.down","description":"DOWN: api","check":{"token":"abcd","alias":"billing-api","url":"https://api.example.com/health"}},
+		{"event":"check.up","check":{"token":"efgh","url":"https://pulse.updown.io/efgh/secret"}},
+		{"event":"check.down","check":{"token":"ijkl","alias":"shop","url":"https://shop.example.com/"}}
+	]`)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	slackBodies := slack.wait(t, 2)
+	assert.ElementsMatch(t, []string{
+		`{"text":"[check.down] billing-api: DOWN: api"}`,
+		`{"text":"[check.down] shop"}`,
+	}, slackBodies)
+
+	// Retried twice after 502 responses
+	teamsBodies := teams.wait(t, 1)
+	var card map[string]string
+	require.NoError(t, json.Unmarshal([]byte(teamsBodies[0]), &card))
+	assert.Equal(t, "MessageCard", card["@type"])
+	assert.Equal(t, "CHECK.DOWN on billing-api", card["text"])
+	assert.Equal(t, "D7263D", card["themeColor"])
+
+	pagerBodies := generic.wait(t, 2)
+	assert.ElementsMatch(t, []string{
+		`{"summary":"DOWN: api","check":"abcd"}`,
+		`{"summary":"","check":"ijkl"}`,
+	}, pagerBodies)
+	assert.Equal(t, "Bearer s3cr3t", generic.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", generic.headers[0].Get("Content-Type"))
+}
+
+func TestRelay_Targets(t *testing.T) {
+	r, err := New(Config{
+		Targets: map[string]TargetConfig{
+			"a": {Type: TargetGeneric, URL: "http://a"},
+			"b": {Type: TargetGeneric, URL: "http://b"},
+		},
+		Routes: []RouteConfig{
+			{Events: []string{updown.EventCheckSSLExpiration}, Targets: []string{"b"}},
+			{Aliases: []string{"db-??"}, Targets: []string{"a", "b"}},
+			{Targets: []string{"a"}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a"}, r.Targets(updown.Event{Type: updown.EventCheckDown}))
+	assert.Equal(t, []string{"b", "a"}, r.Targets(updown.Event{Type: updown.EventCheckSSLExpiration}))
+	assert.Equal(t, []string{"a", "b"}, r.Targets(updown.Event{Type: updown.EventCheckUp, Check: updown.Check{Alias: "db-01"}}))
+	assert.Equal(t, []string{"a"}, r.Targets(updown.Event{Type: updown.EventCheckUp, Check: updown.Check{Alias: "db-001"}}))
+}
+
+func TestRelay_QueueFull(t *testing.T) {
+	r, err := New(Config{
+		Targets:   map[string]TargetConfig{"a": {Type: TargetSlack, URL: "http://a"}},
+		Routes:    []RouteConfig{{Targets: []string{"a"}}},
+		QueueSize: 1,
+	})
+	require.NoError(t, err)
+
+	event := `{"event":"check.down","check":{"token":"abcd"}}`
+	assert.Equal(t, http.StatusNoContent, post(t, r.Handler(), event))
+	assert.Equal(t, http.StatusServiceUnavailable, post(t, r.Handler(), event))
+}
+
+func TestRelay_BatchDoesNotFit(t *testing.T) {
+	r, err := New(Config{
+		Targets:   map[string]TargetConfig{"a": {Type: TargetSlack, URL: "http://a"}},
+		Routes:    []RouteConfig{{Targets: []string{"a"}}},
+		QueueSize: 2,
+	})
+	require.NoError(t, err)
+
+	// No event of a batch is queued when they do not all fit, so that it can be sent again
+	// without duplicates
+	batch := `[{"event":"check.down","check":{"token":"aaaa"}},{"event":"check.down","check":{"token":"bbbb"}},{"event":"check.down","check":{"token":"cccc"}}]`
+	assert.Equal(t, http.StatusInternalServerError, post(t, r.Handler(), batch))
+	assert.Empty(t, r.queue)
+
+	batch = `[{"event":"check.down","check":{"token":"aaaa"}},{"event":"check.down","check":{"token":"bbbb"}}]`
+	assert.Equal(t, http.StatusNoContent, post(t, r.Handler(), batch))
+	assert.Len(t, r.queue, 2)
+}
+
+func TestRelay_Secret(t *testing.T) {
+	r, err := New(Config{
+		Targets: map[string]TargetConfig{"a": {Type: TargetSlack, URL: "http://a"}},
+		Routes:  []RouteConfig{{Targets: []string{"a"}}},
+	})
+	require.NoError(t, err)
+	r.Secret = "s3cr3t"
+
+	event := `{"event":"check.down","check":{"token":"abcd"}}`
+	assert.Equal(t, http.StatusUnauthorized, post(t, r.Handler(), event))
+	assert.Empty(t, r.queue)
+
+	req := httptest.NewRequest(http.MethodPost, "/updown?token=s3cr3t", strings.NewReader(event))
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Len(t, r.queue, 1)
+}
+
+func TestRelay_NoRetryOnClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r, err := New(Config{
+		Targets: map[string]TargetConfig{"a": {Type: TargetGeneric, URL: server.URL}},
+		Routes:  []RouteConfig{{Targets: []string{"a"}}},
+		Retry:   RetryConfig{InitialWait: Duration(time.Millisecond)},
+	})
+	require.NoError(t, err)
+
+	err = r.deliver(context.Background(), delivery{target: r.targets["a"], event: updown.Event{Type: updown.EventCheckUp}})
+	assert.EqualError(t, err, "404 Not Found")
+	assert.Equal(t, 1, calls)
+}
+
+func TestConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "relay.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{
+		"targets": {"ops": {"type": "slack", "url": "https://hooks.slack.com/services/x"}},
+		"routes": [{"events": ["check.down"], "targets": ["ops"]}],
+		"retry": {"max_attempts": 3, "initial_wait": "2s", "max_wait": "1m"}
+	}`), 0o600))
+
+	cfg, err := LoadConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.Retry.MaxAttempts)
+	assert.Equal(t, Duration(2*time.Second), cfg.Retry.InitialWait)
+	_, err = New(cfg)
+	assert.NoError(t, err)
+
+	for msg, cfg := range map[string]Config{
+		"no target is configured": {},
+		"no route is configured": {
+			Targets: map[string]TargetConfig{"a": {Type: TargetSlack, URL: "x"}},
+		},
+		`target a: unknown type "email", expected slack, teams or generic`: {
+			Targets: map[string]TargetConfig{"a": {Type: "email", URL: "x"}},
+		},
+		`route 0: unknown target "b"`: {
+			Targets: map[string]TargetConfig{"a": {Type: TargetSlack, URL: "x"}},
+			Routes:  []RouteConfig{{Targets: []string{"b"}}},
+		},
+		"target a: parsing template: template: a:1: unclosed action": {
+			Targets: map[string]TargetConfig{"a": {Type: TargetSlack, URL: "x", Template: "{{ .Type"}},
+			Routes:  []RouteConfig{{Targets: []string{"a"}}},
+		},
+	} {
+		_, err := New(cfg)
+		assert.EqualError(t, err, msg)
+	}
+}
@@ -0,0 +1,264 @@
+// Package tfgen generates the Terraform configuration of the checks, pulses, recipients and
+// status pages of an updown.io account, with the import blocks adopting them.
+package tfgen
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// Options customizes the generated configuration
+type Options struct {
+	// AliasPrefix is the alias_prefix of the provider, stripped from the aliases of the checks
+	// and pulses carrying it, as the provider adds it back
+	AliasPrefix string
+}
+
+// generator writes the configuration of an account, resolving the recipient IDs and the check
+// tokens to references to the resources generated for them
+type generator struct {
+	body *hclwrite.Body
+	opts Options
+
+	recipientRefs map[string]hcl.Traversal
+	checkRefs     map[string]hcl.Traversal
+
+	recipientNames, dataNames, checkNames, pulseNames, pageNames, variableNames names
+}
+
+// Generate returns the configuration of the resources of an account, each preceded by the
+// Terraform 1.5 import block adopting it. The recipients which cannot be managed, such as the
+// integrations set up from the web UI, are looked up with the updown_recipient data source.
+// Sensitive custom headers are read from variables rather than written in the configuration.
+func Generate(a Account, opts Options) []byte {
+	a = a.sorted()
+
+	f := hclwrite.NewEmptyFile()
+	g := &generator{
+		body:           f.Body(),
+		opts:           opts,
+		recipientRefs:  map[string]hcl.Traversal{},
+		checkRefs:      map[string]hcl.Traversal{},
+		recipientNames: names{},
+		dataNames:      names{},
+		checkNames:     names{},
+		pulseNames:     names{},
+		pageNames:      names{},
+		variableNames:  names{},
+	}
+	g.comment("Generated from the updown.io account by updown-tfgen, review it before applying.")
+
+	// References are resolved in this order, so that recipients and checks are generated
+	// before the resources referring to them
+	for _, r := range a.Recipients {
+		g.recipient(r)
+	}
+	for _, c := range a.Checks {
+		g.check(c)
+	}
+	for _, c := range a.Pulses {
+		g.pulse(c)
+	}
+	for _, p := range a.StatusPages {
+		g.statusPage(p)
+	}
+
+	return f.Bytes()
+}
+
+func traversal(parts ...string) hcl.Traversal {
+	t := hcl.Traversal{hcl.TraverseRoot{Name: parts[0]}}
+	for _, p := range parts[1:] {
+		t = append(t, hcl.TraverseAttr{Name: p})
+	}
+	return t
+}
+
+func (g *generator) comment(text string) {
+	g.body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{Type: hclsyntax.TokenComment, Bytes: []byte("# " + text + "\n")},
+	})
+}
+
+// resource appends the import block of a resource, preceded by the optional comments, and its
+// empty resource block
+func (g *generator) resource(resourceType, name, id string, comments ...string) *hclwrite.Body {
+	g.body.AppendNewline()
+	for _, c := range comments {
+		g.comment(c)
+	}
+	imp := g.body.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", traversal(resourceType, name))
+	imp.SetAttributeValue("id", cty.StringVal(id))
+	g.body.AppendNewline()
+	return g.body.AppendNewBlock("resource", []string{resourceType, name}).Body()
+}
+
+// references sets an attribute to a list of references to the resources of the given IDs,
+// falling back to the IDs themselves for the ones which were not generated
+func references(body *hclwrite.Body, attr string, ids []string, refs map[string]hcl.Traversal) {
+	elems := []hclwrite.Tokens{}
+	for _, id := range ids {
+		if ref, ok := refs[id]; ok {
+			elems = append(elems, hclwrite.TokensForTraversal(ref))
+		} else {
+			elems = append(elems, hclwrite.TokensForValue(cty.StringVal(id)))
+		}
+	}
+	body.SetAttributeRaw(attr, hclwrite.TokensForTuple(elems))
+}
+
+// alias returns the alias of a check without the alias prefix of the options
+func (g *generator) alias(c updown.Check) string {
+	return strings.TrimPrefix(c.Alias, g.opts.AliasPrefix)
+}
+
+func setString(body *hclwrite.Body, attr, value string) {
+	if value != "" {
+		body.SetAttributeValue(attr, cty.StringVal(value))
+	}
+}
+
+func (g *generator) recipient(r updown.Recipient) {
+	label := r.Name
+	if label == "" {
+		label = r.Value
+	}
+
+	if !r.Type.CanBeCreated() || r.Immutable {
+		name := g.dataNames.next(string(r.Type)+"_"+label, string(r.Type))
+		g.body.AppendNewline()
+		g.body.AppendNewBlock("data", []string{"updown_recipient", name}).Body().
+			SetAttributeValue("id", cty.StringVal(r.ID))
+		g.recipientRefs[r.ID] = traversal("data", "updown_recipient", name, "id")
+		return
+	}
+
+	name := g.recipientNames.next(string(r.Type)+"_"+label, string(r.Type))
+	body := g.resource("updown_recipient", name, r.ID)
+	body.SetAttributeValue("type", cty.StringVal(string(r.Type)))
+	body.SetAttributeValue("value", cty.StringVal(r.Value))
+	// The API returns the value as the name of the recipients which are not webhooks
+	if r.Type == updown.RecipientTypeWebhook && r.Name != r.Value {
+		setString(body, "name", r.Name)
+	}
+	g.recipientRefs[r.ID] = traversal("updown_recipient", name, "id")
+}
+
+func (g *generator) check(c updown.Check) {
+	alias := g.alias(c)
+	name := g.checkNames.next(alias, urlLabel(c.URL), c.Token)
+	body := g.resource("updown_check", name, c.Token)
+
+	body.SetAttributeValue("url", cty.StringVal(c.URL))
+	// The type is detected from the scheme of the URL when it is not set
+	if u, err := url.Parse(c.URL); c.Type != "" && (err != nil || u.Scheme != c.Type) {
+		body.SetAttributeValue("type", cty.StringVal(c.Type))
+	}
+	setString(body, "alias", alias)
+	if c.Period != 0 && c.Period != 60 {
+		body.SetAttributeValue("period", cty.NumberIntVal(int64(c.Period)))
+	}
+	if c.Apdex != 0 {
+		body.SetAttributeValue("apdex_t", cty.NumberFloatVal(c.Apdex))
+	}
+	g.checkState(body, c)
+	setString(body, "string_match", c.StringMatch)
+
+	if len(c.DisabledLocations) > 0 {
+		locations := append([]string{}, c.DisabledLocations...)
+		sort.Strings(locations)
+		vals := []cty.Value{}
+		for _, l := range locations {
+			vals = append(vals, cty.StringVal(l))
+		}
+		body.SetAttributeValue("disabled_locations", cty.SetVal(vals))
+	}
+	if len(c.RecipientIDs) > 0 {
+		references(body, "recipients", c.RecipientIDs, g.recipientRefs)
+	}
+	if len(c.CustomHeaders) > 0 {
+		g.customHeaders(body, name, c.CustomHeaders)
+	}
+
+	g.checkRefs[c.Token] = traversal("updown_check", name, "id")
+}
+
+// checkState sets the attributes shared by checks and pulses which differ from their defaults
+func (g *generator) checkState(body *hclwrite.Body, c updown.Check) {
+	if !c.Enabled {
+		body.SetAttributeValue("enabled", cty.False)
+	}
+	if c.Published {
+		body.SetAttributeValue("published", cty.True)
+	}
+	setString(body, "mute_until", c.MuteUntil)
+}
+
+// customHeaders sets the custom headers of a check. The values of the sensitive ones, such as
+// Authorization, are read from sensitive variables declared for them.
+func (g *generator) customHeaders(body *hclwrite.Body, checkName string, headers map[string]string) {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := []hclwrite.ObjectAttrTokens{}
+	for _, k := range keys {
+		value := hclwrite.TokensForValue(cty.StringVal(headers[k]))
+		if updown.IsSensitiveHeader(k) {
+			variable := g.variableNames.next(checkName + "_" + k)
+			value = hclwrite.TokensForTraversal(traversal("var", variable))
+
+			g.body.AppendNewline()
+			v := g.body.AppendNewBlock("variable", []string{variable}).Body()
+			v.SetAttributeValue("description", cty.StringVal("Value of the "+k+" header of updown_check."+checkName))
+			v.SetAttributeTraversal("type", traversal("string"))
+			v.SetAttributeValue("sensitive", cty.True)
+		}
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForValue(cty.StringVal(k)),
+			Value: value,
+		})
+	}
+	body.SetAttributeRaw("custom_headers", hclwrite.TokensForObject(attrs))
+}
+
+func (g *generator) pulse(c updown.Check) {
+	alias := g.alias(c)
+	name := g.pulseNames.next(alias, "pulse_"+c.Token)
+	body := g.resource("updown_pulse", name, c.Token,
+		"The API redacts the secret of pulse URLs, import with the <token>,<pulse_url> ID to keep pulse_url.")
+
+	setString(body, "alias", alias)
+	body.SetAttributeValue("period", cty.NumberIntVal(int64(c.Period)))
+	g.checkState(body, c)
+	if len(c.RecipientIDs) > 0 {
+		references(body, "recipients", c.RecipientIDs, g.recipientRefs)
+	}
+
+	g.checkRefs[c.Token] = traversal("updown_pulse", name, "id")
+}
+
+func (g *generator) statusPage(p updown.StatusPage) {
+	name := g.pageNames.next(p.Name, "status_page_"+p.Token)
+	body := g.resource("updown_status_page", name, p.Token)
+
+	setString(body, "name", p.Name)
+	setString(body, "description", p.Description)
+	if p.Visibility != "" && p.Visibility != "public" {
+		body.SetAttributeValue("visibility", cty.StringVal(p.Visibility))
+	}
+	if len(p.Checks) > 0 {
+		references(body, "checks", p.Checks, g.checkRefs)
+	}
+}
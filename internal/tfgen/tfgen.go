@@ -0,0 +1,129 @@
+// Package tfgen generates the Terraform configuration of the checks, pulses, recipients and
+// status pages of an updown.io account, with the import blocks adopting them.
+package tfgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+// Account holds the resources of an account to generate the configuration of
+type Account struct {
+	Checks      []updown.Check
+	Pulses      []updown.Check
+	Recipients  []updown.Recipient
+	StatusPages []updown.StatusPage
+}
+
+// Read reads the resources of the account of a client
+func Read(client *updown.Client) (Account, error) {
+	var account Account
+
+	checks, _, err := client.Check.List()
+	if err != nil {
+		return Account{}, fmt.Errorf("reading checks from the API: %w", err)
+	}
+	for _, c := range checks {
+		if c.Type == "pulse" {
+			account.Pulses = append(account.Pulses, c)
+		} else {
+			account.Checks = append(account.Checks, c)
+		}
+	}
+
+	if account.Recipients, _, err = client.Recipient.List(); err != nil {
+		return Account{}, fmt.Errorf("reading recipients from the API: %w", err)
+	}
+	if account.StatusPages, _, err = client.StatusPage.List(); err != nil {
+		return Account{}, fmt.Errorf("reading status pages from the API: %w", err)
+	}
+
+	return account, nil
+}
+
+// sorted returns a copy of the account whose resources are in a stable order, so that the
+// generated configuration only changes with the account
+func (a Account) sorted() Account {
+	s := Account{
+		Checks:      append([]updown.Check{}, a.Checks...),
+		Pulses:      append([]updown.Check{}, a.Pulses...),
+		Recipients:  append([]updown.Recipient{}, a.Recipients...),
+		StatusPages: append([]updown.StatusPage{}, a.StatusPages...),
+	}
+	byAlias := func(checks []updown.Check) func(i, j int) bool {
+		return func(i, j int) bool {
+			if checks[i].Alias != checks[j].Alias {
+				return checks[i].Alias < checks[j].Alias
+			}
+			return checks[i].Token < checks[j].Token
+		}
+	}
+	sort.SliceStable(s.Checks, byAlias(s.Checks))
+	sort.SliceStable(s.Pulses, byAlias(s.Pulses))
+	sort.SliceStable(s.Recipients, func(i, j int) bool { return s.Recipients[i].ID < s.Recipients[j].ID })
+	sort.SliceStable(s.StatusPages, func(i, j int) bool {
+		if s.StatusPages[i].Name != s.StatusPages[j].Name {
+			return s.StatusPages[i].Name < s.StatusPages[j].Name
+		}
+		return s.StatusPages[i].Token < s.StatusPages[j].Token
+	})
+	return s
+}
+
+// names hands out the unique names of the resources of a type
+type names map[string]bool
+
+// next returns a valid Terraform name derived from the given labels, the first non empty one
+// being used, with a numeric suffix when it is taken already
+func (n names) next(labels ...string) string {
+	name := ""
+	for _, l := range labels {
+		if name = sanitizeName(l); name != "" {
+			break
+		}
+	}
+	if name == "" {
+		name = "unnamed"
+	}
+	if c := name[0]; c >= '0' && c <= '9' {
+		name = "_" + name
+	}
+
+	unique := name
+	for i := 2; n[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	n[unique] = true
+	return unique
+}
+
+// sanitizeName lowercases a label and replaces its runs of characters which are not letters
+// or digits with underscores
+func sanitizeName(label string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(label) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return b.String()
+}
+
+// urlLabel returns a URL without its scheme, to name the checks without alias
+func urlLabel(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	}
+	return u
+}
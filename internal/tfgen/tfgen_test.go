@@ -0,0 +1,196 @@
+package tfgen
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+)
+
+func TestRead(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/checks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"token":"abcd","type":"https","url":"https://example.com"},{"token":"efgh","type":"pulse","period":3600}]`)
+	})
+	mux.HandleFunc("/api/recipients", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":"email:1","type":"email","value":"ops@example.com"}]`)
+	})
+	mux.HandleFunc("/api/status_pages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"token":"page","name":"Status","checks":["abcd"]}]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := updown.NewClient("api-key", nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	account, err := Read(client)
+	require.NoError(t, err)
+	assert.Equal(t, []updown.Check{{Token: "abcd", Type: "https", URL: "https://example.com"}}, account.Checks)
+	assert.Equal(t, []updown.Check{{Token: "efgh", Type: "pulse", Period: 3600}}, account.Pulses)
+	assert.Equal(t, "email:1", account.Recipients[0].ID)
+	assert.Equal(t, "page", account.StatusPages[0].Token)
+}
+
+func TestGenerate(t *testing.T) {
+	config := Generate(Account{
+		Recipients: []updown.Recipient{
+			{ID: "webhook:3", Type: updown.RecipientTypeWebhook, Name: "Pager", Value: "https://pager.example.com/${id}"},
+			{ID: "email:1", Type: updown.RecipientTypeEmail, Name: "ops@example.com", Value: "ops@example.com"},
+			{ID: "slack:2", Type: updown.RecipientTypeSlack, Name: "#alerts", Value: "alerts"},
+		},
+		Checks: []updown.Check{
+			{Token: "efgh", URL: "example.com", Type: "icmp", Period: 60, Published: true},
+			{
+				Token:             "abcd",
+				URL:               "https://api.example.com/health",
+				Type:              "https",
+				Alias:             "[tf] API",
+				Period:            30,
+				Apdex:             0.5,
+				Enabled:           true,
+				DisabledLocations: []string{"sin", "bhs"},
+				RecipientIDs:      []string{"email:1", "slack:2", "sms:9"},
+				CustomHeaders:     map[string]string{"X-Env": "prod", "Authorization": "Bearer s3cr3t"},
+			},
+		},
+		Pulses: []updown.Check{
+			{Token: "ijkl", Type: "pulse", Alias: "[tf] API", Period: 86400, Enabled: true, RecipientIDs: []string{"webhook:3"}},
+		},
+		StatusPages: []updown.StatusPage{
+			{Token: "page", Name: "Status", Visibility: "protected", Checks: []string{"ijkl", "abcd", "zzzz"}},
+		},
+	}, Options{})
+
+	assert.Equal(t, `# Generated from the updown.io account by updown-tfgen, review it before applying.
+
+import {
+  to = updown_recipient.email_ops_example_com
+  id = "email:1"
+}
+
+resource "updown_recipient" "email_ops_example_com" {
+  type  = "email"
+  value = "ops@example.com"
+}
+
+data "updown_recipient" "slack_alerts" {
+  id = "slack:2"
+}
+
+import {
+  to = updown_recipient.webhook_pager
+  id = "webhook:3"
+}
+
+resource "updown_recipient" "webhook_pager" {
+  type  = "webhook"
+  value = "https://pager.example.com/$${id}"
+  name  = "Pager"
+}
+
+import {
+  to = updown_check.example_com
+  id = "efgh"
+}
+
+resource "updown_check" "example_com" {
+  url       = "example.com"
+  type      = "icmp"
+  enabled   = false
+  published = true
+}
+
+import {
+  to = updown_check.tf_api
+  id = "abcd"
+}
+
+resource "updown_check" "tf_api" {
+  url                = "https://api.example.com/health"
+  alias              = "[tf] API"
+  period             = 30
+  apdex_t            = 0.5
+  disabled_locations = ["bhs", "sin"]
+  recipients         = [updown_recipient.email_ops_example_com.id, data.updown_recipient.slack_alerts.id, "sms:9"]
+  custom_headers = {
+    "Authorization" = var.tf_api_authorization
+    "X-Env"         = "prod"
+  }
+}
+
+variable "tf_api_authorization" {
+  description = "Value of the Authorization header of updown_check.tf_api"
+  type        = string
+  sensitive   = true
+}
+
+# The API redacts the secret of pulse URLs, import with the <token>,<pulse_url> ID to keep pulse_url.
+import {
+  to = updown_pulse.tf_api
+  id = "ijkl"
+}
+
+resource "updown_pulse" "tf_api" {
+  alias      = "[tf] API"
+  period     = 86400
+  recipients = [updown_recipient.webhook_pager.id]
+}
+
+import {
+  to = updown_status_page.status
+  id = "page"
+}
+
+resource "updown_status_page" "status" {
+  name       = "Status"
+  visibility = "protected"
+  checks     = [updown_pulse.tf_api.id, updown_check.tf_api.id, "zzzz"]
+}
+`, string(config))
+
+	_, diags := hclparse.NewParser().ParseHCL(config, "updown.tf")
+	assert.False(t, diags.HasErrors(), diags.Error())
+}
+
+func TestGenerate_AliasPrefix(t *testing.T) {
+	config := string(Generate(Account{
+		Checks: []updown.Check{
+			{Token: "abcd", URL: "https://api.example.com", Type: "https", Alias: "[tf] API", Enabled: true},
+			{Token: "efgh", URL: "https://web.example.com", Type: "https", Alias: "Web", Enabled: true},
+		},
+		Pulses: []updown.Check{
+			{Token: "ijkl", Type: "pulse", Alias: "[tf] Backup", Period: 86400, Enabled: true},
+		},
+	}, Options{AliasPrefix: "[tf] "}))
+
+	// The prefix is stripped from the aliases carrying it, and from the names derived from them
+	assert.Contains(t, config, `resource "updown_check" "api" {
+  url   = "https://api.example.com"
+  alias = "API"
+}`)
+	assert.Contains(t, config, `resource "updown_check" "web" {
+  url   = "https://web.example.com"
+  alias = "Web"
+}`)
+	assert.Contains(t, config, `resource "updown_pulse" "backup" {
+  alias  = "Backup"
+  period = 86400
+}`)
+	assert.NotContains(t, config, "[tf]")
+}
+
+func TestNames(t *testing.T) {
+	n := names{}
+	assert.Equal(t, "tf_backup_job", n.next("[tf] Backup -- Job!"))
+	assert.Equal(t, "tf_backup_job_2", n.next("tf backup job"))
+	assert.Equal(t, "_2xx_api", n.next("", "2xx API"))
+	assert.Equal(t, "unnamed", n.next("", "##"))
+}
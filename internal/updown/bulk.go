@@ -0,0 +1,155 @@
+// Package updown provides a Go client for the updown.io monitoring API.
+package updown
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const defaultBulkWorkers = 4
+
+// CheckResult is the outcome of one item of a bulk operation on checks
+type CheckResult struct {
+	// Token of the check, empty when a check could not be added
+	Token string
+	// Check returned by the API, when the operation succeeded
+	Check Check
+	// HTTP response of the last request made for the item, if any
+	Response *http.Response
+	// Error of the item, nil when it succeeded
+	Err error
+}
+
+// RemoveResult is the outcome of one item of RemoveMany
+type RemoveResult struct {
+	Token    string
+	Deleted  bool
+	Response *http.Response
+	Err      error
+}
+
+// CheckUpdate is one item of UpdateMany
+type CheckUpdate struct {
+	Token string
+	Data  CheckItem
+}
+
+// A BulkError lists the items of a bulk operation which failed
+type BulkError struct {
+	// Errors by index of the failed items in the input
+	Errors map[int]error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d item(s) failed, first: %v", len(e.Errors), e.first())
+}
+
+// Unwrap returns the errors of the failed items
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (e *BulkError) first() error {
+	index := -1
+	for i := range e.Errors {
+		if index < 0 || i < index {
+			index = i
+		}
+	}
+	return fmt.Errorf("item %d: %w", index, e.Errors[index])
+}
+
+// bulkError returns a *BulkError for the items whose error is set, or nil when all succeeded
+func bulkError(n int, errAt func(int) error) error {
+	errs := map[int]error{}
+	for i := 0; i < n; i++ {
+		if err := errAt(i); err != nil {
+			errs[i] = err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BulkError{Errors: errs}
+}
+
+// forEach calls fn for the indexes 0 to n-1 from a pool of BulkWorkers goroutines, and returns
+// once all of them returned. Requests stay limited by the RateLimiter of the client.
+func (c *Client) forEach(n int, fn func(i int)) {
+	workers := c.BulkWorkers
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// AddMany adds the given checks concurrently. It returns one result per item, in the order of
+// the items, and a *BulkError when some of them failed. A failure does not stop the others.
+func (s *CheckService) AddMany(items []CheckItem) ([]CheckResult, error) {
+	results := make([]CheckResult, len(items))
+	s.client.forEach(len(items), func(i int) {
+		check, resp, err := s.Add(items[i])
+		results[i] = CheckResult{Token: check.Token, Check: check, Response: resp, Err: err}
+	})
+	return results, bulkError(len(results), func(i int) error { return results[i].Err })
+}
+
+// UpdateMany updates the given checks concurrently. It returns one result per item, in the
+// order of the items, and a *BulkError when some of them failed. A failure does not stop the
+// others.
+func (s *CheckService) UpdateMany(updates []CheckUpdate) ([]CheckResult, error) {
+	results := make([]CheckResult, len(updates))
+	s.client.forEach(len(updates), func(i int) {
+		check, resp, err := s.Update(updates[i].Token, updates[i].Data)
+		results[i] = CheckResult{Token: updates[i].Token, Check: check, Response: resp, Err: err}
+	})
+	return results, bulkError(len(results), func(i int) error { return results[i].Err })
+}
+
+// RemoveMany removes the given checks concurrently. It returns one result per token, in the
+// order of the tokens, and a *BulkError when some of them failed. A failure does not stop the
+// others.
+func (s *CheckService) RemoveMany(tokens []string) ([]RemoveResult, error) {
+	results := make([]RemoveResult, len(tokens))
+	s.client.forEach(len(tokens), func(i int) {
+		deleted, resp, err := s.Remove(tokens[i])
+		results[i] = RemoveResult{Token: tokens[i], Deleted: deleted, Response: resp, Err: err}
+	})
+	return results, bulkError(len(results), func(i int) error { return results[i].Err })
+}
+
+// GetMany gets the given checks concurrently. It returns one result per token, in the order of
+// the tokens, and a *BulkError when some of them failed. A failure does not stop the others.
+func (s *CheckService) GetMany(tokens []string) ([]CheckResult, error) {
+	results := make([]CheckResult, len(tokens))
+	s.client.forEach(len(tokens), func(i int) {
+		check, resp, err := s.Get(tokens[i])
+		results[i] = CheckResult{Token: tokens[i], Check: check, Response: resp, Err: err}
+	})
+	return results, bulkError(len(results), func(i int) error { return results[i].Err })
+}
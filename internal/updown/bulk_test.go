@@ -0,0 +1,156 @@
+package updown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckService_AddMany(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.BulkWorkers = 3
+
+	var inFlight, maxInFlight int32
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var item CheckItem
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+		if item.Alias == "bad" {
+			writeJSON(w, http.StatusBadRequest, `{"message":"invalid url"}`)
+			return
+		}
+		writeJSON(w, http.StatusCreated, fmt.Sprintf(`{"token":"t-%s","alias":%q}`, item.Alias, item.Alias))
+	})
+
+	items := []CheckItem{}
+	for i := 0; i < 10; i++ {
+		items = append(items, CheckItem{URL: "https://example.com", Alias: fmt.Sprint(i)})
+	}
+	items[4].Alias = "bad"
+
+	results, err := client.Check.AddMany(items)
+	require.Len(t, results, 10)
+	for i, res := range results {
+		if i == 4 {
+			assert.Error(t, res.Err)
+			assert.Empty(t, res.Token)
+			assert.Equal(t, http.StatusBadRequest, res.Response.StatusCode)
+			continue
+		}
+		assert.NoError(t, res.Err)
+		assert.Equal(t, fmt.Sprintf("t-%d", i), res.Token)
+		assert.Equal(t, fmt.Sprint(i), res.Check.Alias)
+	}
+
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	assert.Len(t, bulkErr.Errors, 1)
+	assert.Contains(t, bulkErr.Errors, 4)
+	assert.True(t, strings.HasPrefix(err.Error(), "1 item(s) failed, first: item 4: "))
+
+	var errResp *ErrorResponse
+	assert.True(t, errors.As(err, &errResp))
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+}
+
+func TestCheckService_UpdateMany(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	for _, token := range []string{"abc", "def"} {
+		token := token
+		mux.HandleFunc("/checks/"+token, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			var item CheckItem
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			writeJSON(w, http.StatusOK, fmt.Sprintf(`{"token":%q,"period":%d}`, token, item.Period))
+		})
+	}
+
+	results, err := client.Check.UpdateMany([]CheckUpdate{
+		{Token: "abc", Data: CheckItem{Period: 60}},
+		{Token: "def", Data: CheckItem{Period: 300}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "abc", results[0].Token)
+	assert.Equal(t, 60, results[0].Check.Period)
+	assert.Equal(t, "def", results[1].Token)
+	assert.Equal(t, 300, results[1].Check.Period)
+}
+
+func TestCheckService_RemoveMany(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/checks/abc", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		writeJSON(w, http.StatusOK, `{"deleted":true}`)
+	})
+	mux.HandleFunc("/checks/missing", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusNotFound, `{"message":"not found"}`)
+	})
+
+	results, err := client.Check.RemoveMany([]string{"missing", "abc"})
+	require.Error(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "missing", results[0].Token)
+	assert.Error(t, results[0].Err)
+	assert.False(t, results[0].Deleted)
+	assert.Equal(t, "abc", results[1].Token)
+	assert.NoError(t, results[1].Err)
+	assert.True(t, results[1].Deleted)
+}
+
+func TestCheckService_GetMany(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.RateLimiter = NewRateLimiter(100)
+
+	var calls int32
+	mux.HandleFunc("/checks/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		token := strings.TrimPrefix(r.URL.Path, "/checks/")
+		writeJSON(w, http.StatusOK, fmt.Sprintf(`{"token":%q}`, token))
+	})
+
+	tokens := []string{"a", "b", "c", "d", "e", "f"}
+	start := time.Now()
+	results, err := client.Check.GetMany(tokens)
+	require.NoError(t, err)
+
+	// The first request goes through immediately, the other five are spaced by 10ms
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+	for i, res := range results {
+		assert.Equal(t, tokens[i], res.Check.Token)
+	}
+}
+
+func TestCheckService_GetMany_Empty(t *testing.T) {
+	_, client, teardown := setup()
+	defer teardown()
+
+	results, err := client.Check.GetMany(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -2,6 +2,7 @@
 package updown
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -63,12 +64,27 @@ type CheckItem struct {
 	MuteUntil string `json:"mute_until"`
 	// Disabled monitoring locations. It's an array of abbreviated location names
 	DisabledLocations []string `json:"disabled_locations,omitempty"`
-	// Selected alert recipients. It's an array of recipient IDs
+	// Selected alert recipients. It's an array of recipient IDs. Nil leaves them unchanged on
+	// update, an empty slice removes all of them.
 	RecipientIDs []string `json:"recipients,omitempty"`
 	// The HTTP headers you want in updown requests
 	CustomHeaders map[string]string `json:"custom_headers,omitempty"`
 }
 
+// MarshalJSON encodes the check item, sending an empty list of recipients when RecipientIDs
+// is empty but not nil
+func (c CheckItem) MarshalJSON() ([]byte, error) {
+	type checkItem CheckItem
+	item := struct {
+		checkItem
+		RecipientIDs *[]string `json:"recipients,omitempty"`
+	}{checkItem: checkItem(c)}
+	if c.RecipientIDs != nil {
+		item.RecipientIDs = &c.RecipientIDs
+	}
+	return json.Marshal(item)
+}
+
 // CheckService interacts with the checks section of the API
 type CheckService struct {
 	client *Client
@@ -166,6 +166,29 @@ func TestCheckItem_EmptyStringFieldsSerialized(t *testing.T) {
 	}
 }
 
+func TestCheckItem_Recipients(t *testing.T) {
+	for _, tc := range []struct {
+		recipients []string
+		expected   interface{}
+		exists     bool
+	}{
+		{nil, nil, false},
+		{[]string{}, []interface{}{}, true},
+		{[]string{"email:1"}, []interface{}{"email:1"}, true},
+	} {
+		data, err := json.Marshal(CheckItem{URL: "https://example.com", RecipientIDs: tc.recipients})
+		require.NoError(t, err)
+
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &raw))
+
+		val, exists := raw["recipients"]
+		assert.Equal(t, tc.exists, exists, "%v", tc.recipients)
+		assert.Equal(t, tc.expected, val, "%v", tc.recipients)
+		assert.Equal(t, "https://example.com", raw["url"])
+	}
+}
+
 func TestCheckService_Update_ClearsOptionalStringFields(t *testing.T) {
 	mux, client, teardown := setup()
 	defer teardown()
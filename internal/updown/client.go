@@ -3,12 +3,15 @@ package updown
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
@@ -16,6 +19,9 @@ const (
 	defaultBaseURL = "https://updown.io/api/"
 	userAgent      = "Go Updown v" + libraryVersion
 	mediaType      = "application/json"
+
+	defaultRetryWaitMin = 1 * time.Second
+	defaultRetryWaitMax = 30 * time.Second
 )
 
 // An ErrorResponse reports the error caused by an API request
@@ -29,7 +35,7 @@ type ErrorResponse struct {
 
 func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d %v",
-		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Message)
+		r.Response.Request.Method, RedactURL(r.Response.Request.URL), r.Response.StatusCode, RedactString(r.Message))
 }
 
 // Client manages communication the API
@@ -46,6 +52,33 @@ type Client struct {
 	// APIKey to use for the API
 	APIKey string
 
+	// Maximum number of times a failed request is retried. Rate limited (429) requests are always
+	// retried, network errors and 5xx responses only for idempotent methods.
+	MaxRetries int
+
+	// Minimum and maximum time to wait between retries, the wait doubles on every attempt. The
+	// waits requested by the API with Retry-After are capped to RetryWaitMax too.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// Limits the rate at which requests are sent, nil means unlimited
+	RateLimiter *RateLimiter
+
+	// Number of requests sent concurrently by the bulk operations, such as CheckService.AddMany.
+	// Defaults to 4 when not positive.
+	BulkWorkers int
+
+	// Hooks called around every attempt at sending a request, such as to log them. The requests
+	// and responses given to them have their secrets redacted.
+	Hooks []Hook
+
+	// Middlewares wrapping every call to the API, including its retries, such as to trace them.
+	// The first one is the outermost.
+	Middlewares []Middleware
+
+	// Context of the requests, set with WithContext
+	ctx context.Context
+
 	// Services used for communications with the API
 	Check      CheckService
 	Downtime   DowntimeService
@@ -64,19 +97,34 @@ func NewClient(apiKey string, httpClient *http.Client) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 
 	c := &Client{
-		client:    httpClient,
-		BaseURL:   baseURL,
-		UserAgent: userAgent,
-		APIKey:    apiKey,
+		client:       httpClient,
+		BaseURL:      baseURL,
+		UserAgent:    userAgent,
+		APIKey:       apiKey,
+		RetryWaitMin: defaultRetryWaitMin,
+		RetryWaitMax: defaultRetryWaitMax,
 	}
-	c.Check = CheckService{client: c, cache: NewMemoryCache()}
+	c.setServices(NewMemoryCache())
+
+	return c
+}
+
+func (c *Client) setServices(checkCache Cache) {
+	c.Check = CheckService{client: c, cache: checkCache}
 	c.Downtime = DowntimeService{client: c}
 	c.Metric = MetricService{client: c}
 	c.Node = NodeService{client: c}
 	c.Recipient = RecipientService{client: c}
 	c.StatusPage = StatusPageService{client: c}
+}
 
-	return c
+// WithContext returns a copy of the client sending its requests with ctx, which cancels them
+// and is given to the hooks and middlewares. The copy shares the settings and the cache of c.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	copied.setServices(c.Check.cache)
+	return &copied
 }
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr, which will be resolved to the
@@ -98,14 +146,18 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), buf)
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Add("Content-Type", mediaType)
 	req.Header.Add("Accept", mediaType)
-	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("User-Agent", c.UserAgent)
 	req.Header.Add("X-API-KEY", c.APIKey)
 	return req, nil
 }
@@ -114,7 +166,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 // pointed to by v, or returned as an error if an API error has occurred. If v implements the io.Writer interface,
 // the raw response will be written to v, without attempting to decode it.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	response, err := c.client.Do(req)
+	response, err := c.call(req)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +199,92 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 	return response, err
 }
 
+// send sends the request once the rate limiter allows it, and retries it according to the
+// retry policy of the client.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.RateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		info := c.runBeforeRequest(req, attempt+1)
+		start := time.Now()
+		response, err := c.client.Do(req)
+		err = redactError(err)
+		c.runAfterResponse(req, info, response, err, start)
+
+		if attempt >= c.MaxRetries || !shouldRetry(req, response, err) {
+			return response, err
+		}
+
+		wait := c.retryWait(attempt, response)
+		if response != nil {
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// shouldRetry tells if a request is worth sending again. Non idempotent requests are only
+// retried when the API explicitly rejected them for being rate limited.
+func shouldRetry(req *http.Request, response *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	return err != nil || response.StatusCode >= 500
+}
+
+// retryWait returns how long to wait before the next attempt, honoring the Retry-After header
+// when the API sends one, up to RetryWaitMax so that a server or proxy cannot stall the caller
+// for hours.
+func (c *Client) retryWait(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if s, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && s >= 0 {
+			wait := time.Duration(s) * time.Second
+			if wait > c.RetryWaitMax {
+				wait = c.RetryWaitMax
+			}
+			return wait
+		}
+	}
+
+	wait := c.RetryWaitMin
+	for i := 0; i < attempt && wait < c.RetryWaitMax; i++ {
+		wait *= 2
+	}
+	if wait > c.RetryWaitMax {
+		wait = c.RetryWaitMax
+	}
+	return wait
+}
+
 // CheckResponse checks the API response for errors, and returns them if present. A response is considered an
 // error if it has a status code outside the 200 range. API error responses are expected to have either no response
 // body, or a JSON response body that maps to ErrorResponse. Any other response body will be silently ignored.
@@ -2,13 +2,16 @@ package updown
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,6 +91,32 @@ func TestNewRequest_InvalidURL(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestWithContext(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	type key struct{}
+	var got []interface{}
+	client.Hooks = []Hook{{BeforeRequest: func(ctx context.Context, _ RequestInfo) {
+		got = append(got, ctx.Value(key{}))
+	}}}
+	mux.HandleFunc("/checks/abc", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"token":"abc"}`)
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "resource")
+	_, _, err := client.WithContext(ctx).Check.Get("abc")
+	require.NoError(t, err)
+	_, _, err = client.Check.Get("abc")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"resource", nil}, got)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = client.WithContext(canceled).Check.Get("abc")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestDo_Success(t *testing.T) {
 	mux, client, teardown := setup()
 	defer teardown()
@@ -203,3 +232,120 @@ func TestErrorResponse_Error(t *testing.T) {
 	expected := "GET https://updown.io/api/checks/abc: 404 not found"
 	assert.Equal(t, expected, errResp.Error())
 }
+
+func TestNewRequest_CustomUserAgent(t *testing.T) {
+	c := NewClient("key", nil)
+	c.UserAgent = "Go Updown v0.3 terraform"
+
+	req, err := c.NewRequest("GET", "checks", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Go Updown v0.3 terraform", req.Header.Get("User-Agent"))
+}
+
+func TestDo_RetriesServerErrors(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 2
+	client.RetryWaitMin = time.Millisecond
+
+	var calls int64
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt64(&calls, 1) < 3 {
+			writeJSON(w, http.StatusBadGateway, `{"message":"bad gateway"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `[]`)
+	})
+
+	req, _ := client.NewRequest("GET", "checks", nil)
+	resp, err := client.Do(req, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+}
+
+func TestDo_RetriesExhausted(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 1
+	client.RetryWaitMin = time.Millisecond
+
+	var calls int64
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		writeJSON(w, http.StatusInternalServerError, `{"message":"server error"}`)
+	})
+
+	req, _ := client.NewRequest("GET", "checks", nil)
+	resp, err := client.Do(req, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+}
+
+func TestDo_DoesNotRetryPostOnServerError(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 3
+	client.RetryWaitMin = time.Millisecond
+
+	var calls int64
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		writeJSON(w, http.StatusInternalServerError, `{"message":"server error"}`)
+	})
+
+	req, _ := client.NewRequest("POST", "checks", CheckItem{URL: "https://example.com"})
+	_, err := client.Do(req, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&calls))
+}
+
+func TestDo_RetriesRateLimitedPostWithBody(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 1
+
+	var calls int64
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, r *http.Request) {
+		var item CheckItem
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+		assert.Equal(t, "https://example.com", item.URL)
+
+		if atomic.AddInt64(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusTooManyRequests, `{"message":"slow down"}`)
+			return
+		}
+		writeJSON(w, http.StatusCreated, `{"token":"new"}`)
+	})
+
+	req, _ := client.NewRequest("POST", "checks", CheckItem{URL: "https://example.com"})
+	var check Check
+	_, err := client.Do(req, &check)
+
+	require.NoError(t, err)
+	assert.Equal(t, "new", check.Token)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+}
+
+func TestRetryWait(t *testing.T) {
+	c := NewClient("key", nil)
+	c.RetryWaitMin = time.Second
+	c.RetryWaitMax = 5 * time.Second
+
+	assert.Equal(t, time.Second, c.retryWait(0, nil))
+	assert.Equal(t, 2*time.Second, c.retryWait(1, nil))
+	assert.Equal(t, 4*time.Second, c.retryWait(2, nil))
+	assert.Equal(t, 5*time.Second, c.retryWait(3, nil))
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	assert.Equal(t, 3*time.Second, c.retryWait(0, resp))
+
+	// Retry-After is capped to RetryWaitMax
+	resp = &http.Response{Header: http.Header{"Retry-After": []string{"86400"}}}
+	assert.Equal(t, 5*time.Second, c.retryWait(0, resp))
+}
@@ -9,6 +9,7 @@ import (
 
 // Downtime represents a downtime period for a check
 type Downtime struct {
+	ID        string `json:"id,omitempty"`
 	Error     string `json:"error,omitempty"`
 	StartedAt string `json:"started_at,omitempty"`
 	EndedAt   string `json:"ended_at,omitempty"`
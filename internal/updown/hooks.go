@@ -0,0 +1,154 @@
+// Package updown provides a Go client for the updown.io monitoring API.
+package updown
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// maxLoggedBody is the size over which the bodies given to hooks are truncated
+const maxLoggedBody = 64 << 10
+
+// RequestInfo describes an attempt at sending a request to the API. Its secrets are redacted.
+type RequestInfo struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   string
+	// Attempt is 1 for the first attempt, and increases on every retry
+	Attempt int
+}
+
+// ResponseInfo describes the outcome of an attempt at sending a request to the API. Its
+// secrets are redacted.
+type ResponseInfo struct {
+	Request RequestInfo
+	// StatusCode, Header and Body are empty when no response was received
+	StatusCode int
+	Header     http.Header
+	Body       string
+	Duration   time.Duration
+	// Err is the error which prevented receiving a response
+	Err error
+}
+
+// Hook is called around every attempt at sending a request to the API, including retries.
+// Either function can be nil. Hooks are called synchronously, from the goroutine sending the
+// request, and must not keep the response from being read. The responses are only buffered and
+// redacted when a hook has an AfterResponse function.
+type Hook struct {
+	BeforeRequest func(ctx context.Context, req RequestInfo)
+	AfterResponse func(ctx context.Context, resp ResponseInfo)
+}
+
+// Middleware wraps a call to the API, including its retries. It must call next, possibly with a
+// request derived from req such as with a new context, and return what it returned. The
+// response body must be left unread. Unlike hooks, middlewares see the requests unredacted.
+type Middleware func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error)
+
+// call sends the request through the middlewares of the client
+func (c *Client) call(req *http.Request) (*http.Response, error) {
+	next := c.send
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		m, inner := c.Middlewares[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			return m(req, inner)
+		}
+	}
+	return next(req)
+}
+
+// requestInfo describes an attempt for the hooks
+func requestInfo(req *http.Request, attempt int) RequestInfo {
+	info := RequestInfo{
+		Method:  req.Method,
+		URL:     RedactURL(req.URL),
+		Header:  RedactHeader(req.Header),
+		Attempt: attempt,
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			info.Body = readLoggedBody(body)
+		}
+	}
+
+	return info
+}
+
+// responseInfo describes the outcome of an attempt for the hooks. The body of the response is
+// buffered so that it can still be read by the caller.
+func responseInfo(request RequestInfo, response *http.Response, err error, duration time.Duration) ResponseInfo {
+	info := ResponseInfo{Request: request, Duration: duration, Err: redactError(err)}
+	if response == nil {
+		return info
+	}
+
+	info.StatusCode = response.StatusCode
+	info.Header = RedactHeader(response.Header)
+	if response.Body != nil {
+		data, readErr := ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+		response.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if readErr == nil {
+			info.Body = truncatedBody(data)
+		}
+	}
+
+	return info
+}
+
+func readLoggedBody(body io.ReadCloser) string {
+	defer body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxLoggedBody+1))
+	if err != nil {
+		return ""
+	}
+	return truncatedBody(data)
+}
+
+func truncatedBody(data []byte) string {
+	if len(data) > maxLoggedBody {
+		return RedactString(string(data[:maxLoggedBody])) + "... (truncated)"
+	}
+	return RedactBody(data)
+}
+
+// runBeforeRequest calls the BeforeRequest hooks of the client, and returns the description
+// of the attempt given to them
+func (c *Client) runBeforeRequest(req *http.Request, attempt int) RequestInfo {
+	if len(c.Hooks) == 0 {
+		return RequestInfo{}
+	}
+
+	info := requestInfo(req, attempt)
+	for _, h := range c.Hooks {
+		if h.BeforeRequest != nil {
+			h.BeforeRequest(req.Context(), info)
+		}
+	}
+	return info
+}
+
+// runAfterResponse calls the AfterResponse hooks of the client. The response is only buffered
+// and redacted for them when there are some.
+func (c *Client) runAfterResponse(req *http.Request, request RequestInfo, response *http.Response, err error, start time.Time) {
+	hooked := false
+	for _, h := range c.Hooks {
+		hooked = hooked || h.AfterResponse != nil
+	}
+	if !hooked {
+		return
+	}
+
+	info := responseInfo(request, response, err, time.Since(start))
+	for _, h := range c.Hooks {
+		if h.AfterResponse != nil {
+			h.AfterResponse(req.Context(), info)
+		}
+	}
+}
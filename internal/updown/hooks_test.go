@@ -0,0 +1,158 @@
+package updown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 1
+	client.RetryWaitMin = time.Millisecond
+
+	attempts := 0
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			writeJSON(w, http.StatusTooManyRequests, `{"message":"slow down"}`)
+			return
+		}
+		writeJSON(w, http.StatusCreated, `{"token":"dddd","url":"https://pulse.updown.io/dddd/secret"}`)
+	})
+
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	client.Hooks = []Hook{
+		{BeforeRequest: func(_ context.Context, req RequestInfo) { requests = append(requests, req) }},
+		{AfterResponse: func(_ context.Context, resp ResponseInfo) { responses = append(responses, resp) }},
+	}
+
+	check, _, err := client.Check.Add(CheckItem{
+		Type:          "pulse",
+		Period:        60,
+		CustomHeaders: map[string]string{"Authorization": "Bearer abc"},
+	})
+	require.NoError(t, err)
+
+	// The caller still gets the unredacted response
+	assert.Equal(t, "https://pulse.updown.io/dddd/secret", check.URL)
+
+	require.Len(t, requests, 2)
+	for i, req := range requests {
+		assert.Equal(t, i+1, req.Attempt)
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, Redacted, req.Header.Get("X-API-KEY"))
+		assert.Contains(t, req.Body, `"Authorization":"<redacted>"`)
+		assert.NotContains(t, req.Body, "Bearer")
+	}
+
+	require.Len(t, responses, 2)
+	assert.Equal(t, http.StatusTooManyRequests, responses[0].StatusCode)
+	assert.Equal(t, requests[0], responses[0].Request)
+	assert.Equal(t, http.StatusCreated, responses[1].StatusCode)
+	assert.Contains(t, responses[1].Body, "https://pulse.updown.io/dddd/<redacted>")
+	assert.NotContains(t, responses[1].Body, "secret")
+	assert.NoError(t, responses[1].Err)
+}
+
+func TestHooks_BufferedResponses(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `[]`)
+	})
+
+	var body reflect.Type
+	client.Middlewares = []Middleware{func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		resp, err := next(req)
+		body = reflect.TypeOf(resp.Body)
+		return resp, err
+	}}
+	buffered := reflect.TypeOf(io.NopCloser(bytes.NewReader(nil)))
+
+	// The responses are only buffered for the AfterResponse hooks
+	client.Hooks = []Hook{{BeforeRequest: func(context.Context, RequestInfo) {}}}
+	_, _, err := client.Check.List()
+	require.NoError(t, err)
+	assert.NotEqual(t, buffered, body)
+
+	client.Hooks = append(client.Hooks, Hook{AfterResponse: func(context.Context, ResponseInfo) {}})
+	_, _, err = client.Check.List()
+	require.NoError(t, err)
+	assert.Equal(t, buffered, body)
+}
+
+func TestHooks_TransportError(t *testing.T) {
+	client := NewClient("test-api-key", nil)
+	client.BaseURL, _ = url.Parse("http://127.0.0.1:1/")
+
+	var responses []ResponseInfo
+	client.Hooks = []Hook{{AfterResponse: func(_ context.Context, resp ResponseInfo) { responses = append(responses, resp) }}}
+
+	req, err := client.NewRequest("GET", "checks?api-key=abc", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "abc")
+	assert.Contains(t, err.Error(), "api-key=<redacted>")
+
+	require.Len(t, responses, 1)
+	assert.Error(t, responses[0].Err)
+	assert.Zero(t, responses[0].StatusCode)
+	assert.Equal(t, "http://127.0.0.1:1/checks?api-key=<redacted>", responses[0].Request.URL)
+}
+
+func TestMiddlewares(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+	client.MaxRetries = 1
+	client.RetryWaitMin = time.Millisecond
+
+	attempts := 0
+	mux.HandleFunc("/checks", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			writeJSON(w, http.StatusTooManyRequests, `{"message":"slow down"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `[]`)
+	})
+
+	type key struct{}
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+			calls = append(calls, "before "+name)
+			ctx := context.WithValue(req.Context(), key{}, name)
+			resp, err := next(req.WithContext(ctx))
+			calls = append(calls, fmt.Sprintf("after %s %d", name, resp.StatusCode))
+			return resp, err
+		}
+	}
+	client.Middlewares = []Middleware{middleware("outer"), middleware("inner")}
+	client.Hooks = []Hook{{BeforeRequest: func(ctx context.Context, req RequestInfo) {
+		calls = append(calls, fmt.Sprintf("attempt %d from %s", req.Attempt, ctx.Value(key{})))
+	}}}
+
+	_, _, err := client.Check.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"before outer",
+		"before inner",
+		"attempt 1 from inner",
+		"attempt 2 from inner",
+		"after inner 200",
+		"after outer 200",
+	}, calls)
+}
@@ -0,0 +1,192 @@
+// Package otelupdown instruments the updown.io API client with OpenTelemetry traces and metrics.
+package otelupdown
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer and the meter of the package
+const instrumentationName = "github.com/Nastaliss/terraform-provider-updown/internal/updown/otelupdown"
+
+// ServiceKey is the attribute holding the section of the API called, such as checks
+const ServiceKey = attribute.Key("updown.service")
+
+// Metric names
+const (
+	DurationMetric = "updown.client.request.duration"
+	ErrorsMetric   = "updown.client.errors"
+)
+
+// placeholders replaces the IDs in the path templates, by section of the API
+var placeholders = map[string]string{
+	"checks":       "{token}",
+	"recipients":   "{id}",
+	"status_pages": "{token}",
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, instead of the global one
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider, instead of the global one
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// instrumentation records the calls of a client
+type instrumentation struct {
+	client   *updown.Client
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// callState is shared between the span of a call and the hook counting its attempts
+type callState struct {
+	attempts int
+}
+
+type callStateKey struct{}
+
+// Instrument makes client create a span for every call to the API, and record its duration and
+// its errors. A call includes its retries, which are counted in the http.request.resend_count
+// attribute of the span and added to it as events. The context of the requests, such as set
+// with http.Request.WithContext before Client.Do, is the parent of the spans.
+//
+// Instrument must be called before the client is used.
+func Instrument(client *updown.Client, opts ...Option) error {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram(DurationMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the calls to the updown.io API, including their retries."))
+	if err != nil {
+		return err
+	}
+	errors, err := meter.Int64Counter(ErrorsMetric,
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of calls to the updown.io API which failed."))
+	if err != nil {
+		return err
+	}
+
+	i := &instrumentation{
+		client:   client,
+		tracer:   cfg.tracerProvider.Tracer(instrumentationName),
+		duration: duration,
+		errors:   errors,
+	}
+	client.Middlewares = append(client.Middlewares, i.middleware)
+	client.Hooks = append(client.Hooks, updown.Hook{BeforeRequest: i.beforeRequest})
+
+	return nil
+}
+
+// route returns the section of the API of a request, and its path template such as
+// checks/{token}/metrics
+func (i *instrumentation) route(u *url.URL) (string, string) {
+	path := strings.Trim(strings.TrimPrefix(u.Path, i.client.BaseURL.Path), "/")
+	parts := strings.Split(path, "/")
+	if p, ok := placeholders[parts[0]]; ok && len(parts) > 1 {
+		parts[1] = p
+	}
+	return parts[0], strings.Join(parts, "/")
+}
+
+func (i *instrumentation) middleware(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	service, route := i.route(req.URL)
+	attrs := []attribute.KeyValue{
+		ServiceKey.String(service),
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLTemplate(route),
+	}
+
+	ctx, span := i.tracer.Start(req.Context(), req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(
+			semconv.URLFull(updown.RedactURL(req.URL)),
+			semconv.ServerAddress(req.URL.Hostname()),
+		))
+	defer span.End()
+
+	state := &callState{}
+	ctx = context.WithValue(ctx, callStateKey{}, state)
+
+	start := time.Now()
+	resp, err := next(req.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	if state.attempts > 1 {
+		span.SetAttributes(semconv.HTTPRequestResendCount(state.attempts - 1))
+	}
+
+	errorType := ""
+	switch {
+	case err != nil:
+		errorType = "transport"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case resp.StatusCode >= 400:
+		errorType = strconv.Itoa(resp.StatusCode)
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	if resp != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	}
+	if errorType != "" {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(errorType))
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType))
+		i.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+
+	return resp, err
+}
+
+// beforeRequest counts the attempts of a call, adding an event to its span for every retry
+func (i *instrumentation) beforeRequest(ctx context.Context, req updown.RequestInfo) {
+	state, ok := ctx.Value(callStateKey{}).(*callState)
+	if !ok {
+		return
+	}
+
+	state.attempts = req.Attempt
+	if req.Attempt > 1 {
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(semconv.HTTPRequestResendCount(req.Attempt-1)))
+	}
+}
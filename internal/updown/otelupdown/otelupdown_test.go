@@ -0,0 +1,161 @@
+package otelupdown
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Nastaliss/terraform-provider-updown/internal/updown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setup returns a client talking to a test server under /api/, instrumented with in memory
+// exporters
+func setup(t *testing.T) (*http.ServeMux, *updown.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(http.StripPrefix("/api", mux))
+	t.Cleanup(server.Close)
+
+	client := updown.NewClient("test-api-key", nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/api/")
+	client.MaxRetries = 2
+	client.RetryWaitMin = time.Millisecond
+
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	require.NoError(t, Instrument(client, WithTracerProvider(tp), WithMeterProvider(mp)))
+	return mux, client, spans, reader
+}
+
+func spanAttributes(s tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestInstrument_Spans(t *testing.T) {
+	mux, client, spans, _ := setup(t)
+
+	attempts := 0
+	mux.HandleFunc("/checks/abcd", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"token":"abcd"}`)
+	})
+	mux.HandleFunc("/checks/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := client.Check.Get("abcd")
+	require.NoError(t, err)
+	_, _, err = client.Check.Get("missing")
+	require.Error(t, err)
+
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 2)
+
+	ok := stubs[0]
+	assert.Equal(t, "GET checks/{token}", ok.Name)
+	assert.Equal(t, trace.SpanKindClient, ok.SpanKind)
+	assert.Equal(t, codes.Unset, ok.Status.Code)
+	attrs := spanAttributes(ok)
+	assert.Equal(t, "checks", attrs[ServiceKey].AsString())
+	assert.Equal(t, "GET", attrs["http.request.method"].AsString())
+	assert.Equal(t, "checks/{token}", attrs["url.template"].AsString())
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(2), attrs["http.request.resend_count"].AsInt64())
+	require.Len(t, ok.Events, 2)
+	assert.Equal(t, "retry", ok.Events[0].Name)
+
+	failed := stubs[1]
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	attrs = spanAttributes(failed)
+	assert.Equal(t, int64(404), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, "404", attrs["error.type"].AsString())
+	assert.NotContains(t, attrs, attribute.Key("http.request.resend_count"))
+}
+
+func TestInstrument_ParentSpan(t *testing.T) {
+	mux, client, spans, _ := setup(t)
+
+	mux.HandleFunc("/checks/abcd/downtimes", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	req, err := client.NewRequest("GET", "checks/abcd/downtimes?page=1", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req.WithContext(ctx), nil)
+	require.NoError(t, err)
+	parent.End()
+
+	stubs := spans.GetSpans()
+	require.Len(t, stubs, 1)
+	assert.Equal(t, "GET checks/{token}/downtimes", stubs[0].Name)
+	assert.Equal(t, parent.SpanContext().TraceID(), stubs[0].SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), stubs[0].Parent.SpanID())
+}
+
+func TestInstrument_Metrics(t *testing.T) {
+	mux, client, _, reader := setup(t)
+
+	mux.HandleFunc("/recipients", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/recipients/42", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, _, err := client.Recipient.List()
+	require.NoError(t, err)
+	_, _, err = client.Recipient.List()
+	require.NoError(t, err)
+	_, _, err = client.Recipient.Remove("42")
+	require.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	durations := metrics[DurationMetric].Data.(metricdata.Histogram[float64]).DataPoints
+	counts := map[string]uint64{}
+	for _, dp := range durations {
+		route, _ := dp.Attributes.Value("url.template")
+		method, _ := dp.Attributes.Value("http.request.method")
+		counts[method.AsString()+" "+route.AsString()] = dp.Count
+	}
+	assert.Equal(t, map[string]uint64{"GET recipients": 2, "DELETE recipients/{id}": 1}, counts)
+
+	errors := metrics[ErrorsMetric].Data.(metricdata.Sum[int64]).DataPoints
+	require.Len(t, errors, 1)
+	assert.Equal(t, int64(1), errors[0].Value)
+	errorType, _ := errors[0].Attributes.Value("error.type")
+	assert.Equal(t, "400", errorType.AsString())
+}
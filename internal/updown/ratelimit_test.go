@@ -0,0 +1,38 @@
+package updown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(0))
+	assert.Nil(t, NewRateLimiter(-1))
+
+	var l *RateLimiter
+	assert.NoError(t, l.Wait(context.Background()))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(100)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, l.Wait(context.Background()))
+	}
+
+	// The first request goes through immediately, the other four are spaced by 10ms
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	l := NewRateLimiter(0.001)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, l.Wait(ctx))
+}
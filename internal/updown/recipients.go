@@ -18,12 +18,43 @@ const (
 	RecipientTypeMSTeams         RecipientType = "msteams"
 )
 
+// Recipient types of the integrations which can only be set up from the web UI. The API may
+// return other types, which are preserved as is.
+const (
+	RecipientTypeSlack      RecipientType = "slack"
+	RecipientTypeTelegram   RecipientType = "telegram"
+	RecipientTypeZapier     RecipientType = "zapier"
+	RecipientTypePagerDuty  RecipientType = "pagerduty"
+	RecipientTypeStatuspage RecipientType = "statuspage"
+)
+
+// RecipientTypes lists the recipient types which can be created through the API
+var RecipientTypes = []RecipientType{
+	RecipientTypeEmail,
+	RecipientTypeSMS,
+	RecipientTypeWebhook,
+	RecipientTypeSlackCompatible,
+	RecipientTypeMSTeams,
+}
+
+// CanBeCreated tells if recipients of this type can be created through the API
+func (t RecipientType) CanBeCreated() bool {
+	for _, c := range RecipientTypes {
+		if c == t {
+			return true
+		}
+	}
+	return false
+}
+
 // Recipient represents a recipient/channel for alerts
 type Recipient struct {
 	ID    string        `json:"id,omitempty"`
 	Type  RecipientType `json:"type,omitempty"`
 	Name  string        `json:"name,omitempty"`
 	Value string        `json:"value,omitempty"`
+	// Immutable recipients are managed by updown.io or the web UI and cannot be removed
+	Immutable bool `json:"immutable,omitempty"`
 }
 
 // RecipientItem represents a new recipient you want to create
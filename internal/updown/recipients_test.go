@@ -94,3 +94,34 @@ func TestRecipientService_Remove_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
+
+func TestRecipientService_List_UIOnlyTypes(t *testing.T) {
+	mux, client, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/recipients", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `[
+			{"id":"slack:123","type":"slack","name":"#alerts","immutable":true},
+			{"id":"discord:456","type":"discord","name":"Ops"}
+		]`)
+	})
+
+	recipients, _, err := client.Recipient.List()
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+
+	assert.Equal(t, RecipientTypeSlack, recipients[0].Type)
+	assert.True(t, recipients[0].Immutable)
+	assert.False(t, recipients[0].Type.CanBeCreated())
+
+	assert.Equal(t, RecipientType("discord"), recipients[1].Type)
+	assert.Equal(t, "Ops", recipients[1].Name)
+	assert.False(t, recipients[1].Type.CanBeCreated())
+}
+
+func TestRecipientType_CanBeCreated(t *testing.T) {
+	for _, recipientType := range RecipientTypes {
+		assert.True(t, recipientType.CanBeCreated(), recipientType)
+	}
+	assert.False(t, RecipientTypeTelegram.CanBeCreated())
+}
@@ -0,0 +1,136 @@
+// Package updown provides a Go client for the updown.io monitoring API.
+package updown
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the secrets removed from logs and errors, as done by the API for the
+// secret part of pulse URLs
+const Redacted = "<redacted>"
+
+// sensitiveHeaders are the headers whose values are always secret
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// sensitiveNameParts are the words marking the names of headers and query parameters whose
+// values are secret, such as X-Auth-Token
+var sensitiveNameParts = []string{"auth", "key", "password", "secret", "session", "signature", "token"}
+
+// pulseURLPattern matches the URLs of pulse checks, https://pulse.updown.io/<token>/<secret>,
+// the secret being in the last part
+var pulseURLPattern = regexp.MustCompile(`(https?://pulse\.[A-Za-z0-9.-]+(?::\d+)?/[^/\s"'\\]+/)[^/\s"'\\?#<>]+`)
+
+// queryParamPattern matches the parameters of a query string
+var queryParamPattern = regexp.MustCompile(`([?&]([^=&\s"'#]+)=)([^&\s"'#]+)`)
+
+// IsSensitiveHeader tells if the value of a header, or of a query parameter, is secret
+func IsSensitiveHeader(name string) bool {
+	if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+		return true
+	}
+
+	name = strings.ToLower(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeader returns a copy of h whose sensitive values are redacted
+func RedactHeader(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for name, values := range h {
+		if IsSensitiveHeader(name) {
+			values = []string{Redacted}
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// RedactURL returns u with the secret of pulse URLs and the sensitive query parameters, such as
+// api-key, redacted
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return RedactString(u.String())
+}
+
+// RedactString redacts the secrets of the pulse URLs and of the sensitive query parameters
+// found in s
+func RedactString(s string) string {
+	s = pulseURLPattern.ReplaceAllString(s, "${1}"+Redacted)
+	return queryParamPattern.ReplaceAllStringFunc(s, func(param string) string {
+		m := queryParamPattern.FindStringSubmatch(param)
+		if name, err := url.QueryUnescape(m[2]); err == nil && IsSensitiveHeader(name) {
+			return m[1] + Redacted
+		}
+		return param
+	})
+}
+
+// RedactBody returns the body of a request or response with its secrets redacted. In JSON
+// bodies, the values of the sensitive custom headers of checks are redacted as well.
+func RedactBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return RedactString(string(body))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactJSON(v, "")); err != nil {
+		return RedactString(string(body))
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactJSON redacts the secrets of a decoded JSON value, found under the given key
+func redactJSON(v interface{}, key string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if key == "custom_headers" && IsSensitiveHeader(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redactJSON(item, k)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item, key)
+		}
+		return v
+	case string:
+		return RedactString(v)
+	default:
+		return v
+	}
+}
+
+// redactError redacts the URL of the errors returned by the HTTP client
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	redacted := *urlErr
+	redacted.URL = RedactString(urlErr.URL)
+	return &redacted
+}
@@ -0,0 +1,75 @@
+package updown
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSensitiveHeader(t *testing.T) {
+	for _, name := range []string{"X-API-KEY", "authorization", "Cookie", "X-Auth-Token", "api-key", "Client-Secret"} {
+		assert.True(t, IsSensitiveHeader(name), name)
+	}
+	for _, name := range []string{"Content-Type", "Accept", "User-Agent", "X-Request-Id"} {
+		assert.False(t, IsSensitiveHeader(name), name)
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-API-KEY", "secret-key")
+	h.Set("Accept", "application/json")
+
+	redacted := RedactHeader(h)
+	assert.Equal(t, Redacted, redacted.Get("X-API-KEY"))
+	assert.Equal(t, "application/json", redacted.Get("Accept"))
+	assert.Equal(t, "secret-key", h.Get("X-API-KEY"))
+}
+
+func TestRedactString(t *testing.T) {
+	cases := map[string]string{
+		"https://pulse.updown.io/dddd/secret":                 "https://pulse.updown.io/dddd/<redacted>",
+		"https://pulse.updown.io/dddd/<redacted>":             "https://pulse.updown.io/dddd/<redacted>",
+		"pinged https://pulse.updown.io/dddd/s3cr3t?x=1 ok":   "pinged https://pulse.updown.io/dddd/<redacted>?x=1 ok",
+		"https://updown.io/api/checks?api-key=abc&limit=10":   "https://updown.io/api/checks?api-key=<redacted>&limit=10",
+		"https://updown.io/api/checks/abcd":                   "https://updown.io/api/checks/abcd",
+		"https://example.com/health?token=abc&format=json#up": "https://example.com/health?token=<redacted>&format=json#up",
+	}
+	for in, expected := range cases {
+		assert.Equal(t, expected, RedactString(in), in)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://updown.io/api/checks?api-key=abc")
+	assert.Equal(t, "https://updown.io/api/checks?api-key=<redacted>", RedactURL(u))
+	assert.Empty(t, RedactURL(nil))
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"url":"https://pulse.updown.io/dddd/secret","alias":"a&b",` +
+		`"custom_headers":{"Authorization":"Bearer abc","X-Env":"prod"}}`
+	assert.JSONEq(t, `{"url":"https://pulse.updown.io/dddd/<redacted>","alias":"a&b",`+
+		`"custom_headers":{"Authorization":"<redacted>","X-Env":"prod"}}`, RedactBody([]byte(body)))
+
+	list := `[{"token":"abcd","custom_headers":{"Cookie":"session=1"}}]`
+	assert.JSONEq(t, `[{"token":"abcd","custom_headers":{"Cookie":"<redacted>"}}]`, RedactBody([]byte(list)))
+
+	assert.Equal(t, "see https://pulse.updown.io/dddd/<redacted>", RedactBody([]byte("see https://pulse.updown.io/dddd/key")))
+}
+
+func TestErrorResponse_ErrorRedacted(t *testing.T) {
+	u, _ := url.Parse("https://updown.io/api/checks?api-key=abc")
+	errResp := &ErrorResponse{
+		Response: &http.Response{
+			StatusCode: 400,
+			Request:    &http.Request{Method: "POST", URL: u},
+		},
+		Message: "invalid url https://pulse.updown.io/dddd/secret",
+	}
+
+	expected := "POST https://updown.io/api/checks?api-key=<redacted>: 400 invalid url https://pulse.updown.io/dddd/<redacted>"
+	assert.Equal(t, expected, errResp.Error())
+}
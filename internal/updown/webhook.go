@@ -0,0 +1,255 @@
+// Package updown provides a Go client for the updown.io monitoring API.
+package updown
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+)
+
+// Types of the events sent to webhook recipients
+const (
+	EventCheckDown            = "check.down"
+	EventCheckUp              = "check.up"
+	EventCheckSSLInvalid      = "check.ssl_invalid"
+	EventCheckSSLValid        = "check.ssl_valid"
+	EventCheckSSLExpiration   = "check.ssl_expiration"
+	EventCheckSSLRenewed      = "check.ssl_renewed"
+	EventCheckPerformanceDrop = "check.performance_drop"
+)
+
+// defaultMaxWebhookBody is the size over which WebhookHandler rejects requests by default
+const defaultMaxWebhookBody = 1 << 20
+
+// Header and query parameter carrying the secret of the webhook requests. As updown.io only
+// sends the URL of the recipient, the secret is usually set as a query parameter of that URL.
+const (
+	WebhookSecretHeader = "X-Webhook-Secret"
+	WebhookSecretParam  = "token"
+)
+
+// SSLCert represents an SSL certificate in a webhook event
+type SSLCert struct {
+	Subject   string `json:"subject,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// EventSSL represents the SSL section of the check.ssl_* events
+type EventSSL struct {
+	// Certificate of the check, unset for check.ssl_renewed
+	Cert *SSLCert `json:"cert,omitempty"`
+	// Previous and new certificates, for check.ssl_renewed
+	OldCert *SSLCert `json:"old_cert,omitempty"`
+	NewCert *SSLCert `json:"new_cert,omitempty"`
+	// Reason why the certificate is invalid, for check.ssl_invalid
+	Error string `json:"error,omitempty"`
+	// Number of days left before the certificate expires, for check.ssl_expiration
+	DaysBeforeExpiration int `json:"days_before_expiration,omitempty"`
+}
+
+// Event represents an event sent by updown.io to webhook recipients. The sections which do not
+// apply to its type are nil.
+type Event struct {
+	// Type of the event, such as check.down
+	Type        string `json:"event"`
+	Time        string `json:"time,omitempty"`
+	Description string `json:"description,omitempty"`
+	// State of the check at the time of the event
+	Check Check `json:"check"`
+	// Downtime which started or ended, for check.down and check.up
+	Downtime *Downtime `json:"downtime,omitempty"`
+	// SSL certificate of the check, for the check.ssl_* events
+	SSL *EventSSL `json:"ssl,omitempty"`
+	// Drop of the APDEX of the check, such as "0.9 to 0.6", and the metrics causing it, by time,
+	// for check.performance_drop
+	ApdexDropped string                `json:"apdex_dropped,omitempty"`
+	LastMetrics  map[string]MetricItem `json:"last_metrics,omitempty"`
+	// Raw is the payload of the event, including the fields not modelled above
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an event, keeping its raw payload
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	var decoded event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*e = Event(decoded)
+	e.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// DecodeEvents decodes the body of a webhook request. updown.io sends batches of events as a
+// JSON array, a single event object is accepted as well.
+func DecodeEvents(r io.Reader) ([]Event, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("decoding event: %w", err)
+		}
+		return []Event{e}, nil
+	}
+
+	var events []Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("decoding events: %w", err)
+	}
+	return events, nil
+}
+
+// EventCallback handles an event. Returning an error makes the webhook request fail.
+type EventCallback func(ctx context.Context, e Event) error
+
+// WebhookHandler is an http.Handler receiving the events posted by updown.io to webhook
+// recipients, and calling the callback of their type. The events of a batch are handled in
+// order, until a callback fails. The request then fails with a 500 status, so callbacks should
+// handle the redelivery of the events which succeeded before. The error of the callback is
+// logged, not returned to the sender.
+type WebhookHandler struct {
+	OnCheckDown            EventCallback
+	OnCheckUp              EventCallback
+	OnCheckSSLInvalid      EventCallback
+	OnCheckSSLValid        EventCallback
+	OnCheckSSLExpiration   EventCallback
+	OnCheckSSLRenewed      EventCallback
+	OnCheckPerformanceDrop EventCallback
+
+	// OnEvent is called for the events whose type has no callback, including the types unknown
+	// to this package. Such events are ignored when it is nil.
+	OnEvent EventCallback
+
+	// OnEvents, when set, is called with all the events of a request instead of the callbacks
+	// above, such as to accept or reject them together
+	OnEvents func(ctx context.Context, events []Event) error
+
+	// MaxBodySize is the size in bytes over which requests are rejected, 1MiB when not positive
+	MaxBodySize int64
+
+	// Secret, when set, must be given by the requests in the WebhookSecretHeader header or the
+	// WebhookSecretParam query parameter, such as https://example.com/updown?token=<secret>.
+	// Requests without it are rejected with a 401 status.
+	Secret string
+
+	// ErrorLog logs the errors of the callbacks, the standard logger is used when nil
+	ErrorLog *log.Logger
+}
+
+// callback returns the callback of an event type
+func (h *WebhookHandler) callback(eventType string) EventCallback {
+	var cb EventCallback
+	switch eventType {
+	case EventCheckDown:
+		cb = h.OnCheckDown
+	case EventCheckUp:
+		cb = h.OnCheckUp
+	case EventCheckSSLInvalid:
+		cb = h.OnCheckSSLInvalid
+	case EventCheckSSLValid:
+		cb = h.OnCheckSSLValid
+	case EventCheckSSLExpiration:
+		cb = h.OnCheckSSLExpiration
+	case EventCheckSSLRenewed:
+		cb = h.OnCheckSSLRenewed
+	case EventCheckPerformanceDrop:
+		cb = h.OnCheckPerformanceDrop
+	}
+
+	if cb == nil {
+		return h.OnEvent
+	}
+	return cb
+}
+
+// Dispatch calls the callbacks of the events in order, stopping at the first which fails
+func (h *WebhookHandler) Dispatch(ctx context.Context, events []Event) error {
+	for i, e := range events {
+		cb := h.callback(e.Type)
+		if cb == nil {
+			continue
+		}
+		if err := cb(ctx, e); err != nil {
+			return fmt.Errorf("handling event %d (%s of check %s): %w", i, e.Type, e.Check.Token, err)
+		}
+	}
+	return nil
+}
+
+// authorized tells if a request carries the secret of the handler, when it has one
+func (h *WebhookHandler) authorized(r *http.Request) bool {
+	if h.Secret == "" {
+		return true
+	}
+
+	secret := r.Header.Get(WebhookSecretHeader)
+	if secret == "" {
+		secret = r.URL.Query().Get(WebhookSecretParam)
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(h.Secret)) == 1
+}
+
+func (h *WebhookHandler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := h.MaxBodySize
+	if limit <= 0 {
+		limit = defaultMaxWebhookBody
+	}
+
+	events, err := DecodeEvents(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dispatch := h.Dispatch
+	if h.OnEvents != nil {
+		dispatch = h.OnEvents
+	}
+	if err := dispatch(r.Context(), events); err != nil {
+		h.logf("[ERROR] webhook: %s", err)
+		http.Error(w, "handling the events failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,214 @@
+package updown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const webhookBatch = `[
+	{
+		"event": "check.down",
+		"time": "2026-10-18T10:00:00Z",
+		"description": "DOWN: https://example.com/ since 10:00:00",
+		"check": {"token": "abcd", "url": "https://example.com/", "alias": "web", "down": true, "enabled": true},
+		"downtime": {"id": "dt1", "error": "500 Internal Server Error", "started_at": "2026-10-18T10:00:00Z", "ended_at": null, "duration": null}
+	},
+	{
+		"event": "check.ssl_expiration",
+		"time": "2026-10-18T10:05:00Z",
+		"check": {"token": "abcd"},
+		"ssl": {"cert": {"subject": "example.com", "issuer": "R3", "to": "2026-11-01T00:00:00Z"}, "days_before_expiration": 14}
+	},
+	{
+		"event": "check.ssl_renewed",
+		"check": {"token": "abcd"},
+		"ssl": {"old_cert": {"subject": "example.com", "to": "2026-11-01T00:00:00Z"}, "new_cert": {"subject": "example.com", "to": "2027-01-30T00:00:00Z"}}
+	},
+	{
+		"event": "check.performance_drop",
+		"check": {"token": "abcd"},
+		"apdex_dropped": "0.9 to 0.6",
+		"last_metrics": {"2026-10-18T10:00:00Z": {"apdex": 0.6, "timings": {"total": 900}}}
+	},
+	{
+		"event": "check.up",
+		"check": {"token": "abcd", "down": false},
+		"downtime": {"id": "dt1", "error": "500 Internal Server Error", "started_at": "2026-10-18T10:00:00Z", "ended_at": "2026-10-18T10:10:00Z", "duration": 600}
+	},
+	{
+		"event": "check.something_new",
+		"check": {"token": "abcd"},
+		"extra": 42
+	}
+]`
+
+func TestDecodeEvents(t *testing.T) {
+	events, err := DecodeEvents(strings.NewReader(webhookBatch))
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+
+	down := events[0]
+	assert.Equal(t, EventCheckDown, down.Type)
+	assert.Equal(t, "2026-10-18T10:00:00Z", down.Time)
+	assert.Equal(t, "abcd", down.Check.Token)
+	assert.True(t, down.Check.Down)
+	require.NotNil(t, down.Downtime)
+	assert.Equal(t, Downtime{ID: "dt1", Error: "500 Internal Server Error", StartedAt: "2026-10-18T10:00:00Z"}, *down.Downtime)
+	assert.Nil(t, down.SSL)
+
+	expiration := events[1]
+	require.NotNil(t, expiration.SSL)
+	assert.Equal(t, 14, expiration.SSL.DaysBeforeExpiration)
+	assert.Equal(t, "R3", expiration.SSL.Cert.Issuer)
+
+	renewed := events[2]
+	assert.Equal(t, "2026-11-01T00:00:00Z", renewed.SSL.OldCert.To)
+	assert.Equal(t, "2027-01-30T00:00:00Z", renewed.SSL.NewCert.To)
+
+	drop := events[3]
+	assert.Equal(t, "0.9 to 0.6", drop.ApdexDropped)
+	assert.Equal(t, 900, drop.LastMetrics["2026-10-18T10:00:00Z"].Timings.Total)
+
+	up := events[4]
+	assert.Equal(t, 600, up.Downtime.Duration)
+	assert.Equal(t, "2026-10-18T10:10:00Z", up.Downtime.EndedAt)
+
+	unknown := events[5]
+	assert.Equal(t, "check.something_new", unknown.Type)
+	assert.Contains(t, string(unknown.Raw), `"extra": 42`)
+}
+
+func TestDecodeEvents_Single(t *testing.T) {
+	events, err := DecodeEvents(strings.NewReader(` {"event":"check.up","check":{"token":"abcd"}}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCheckUp, events[0].Type)
+}
+
+func TestDecodeEvents_Invalid(t *testing.T) {
+	_, err := DecodeEvents(strings.NewReader(`[{"event":`))
+	assert.Error(t, err)
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var handled []string
+	record := func(ctx context.Context, e Event) error {
+		assert.NotNil(t, ctx)
+		handled = append(handled, e.Type)
+		return nil
+	}
+	h := &WebhookHandler{
+		OnCheckDown:          record,
+		OnCheckUp:            record,
+		OnCheckSSLExpiration: record,
+		OnEvent: func(_ context.Context, e Event) error {
+			handled = append(handled, "other "+e.Type)
+			return nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(webhookBatch))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{
+		"check.down",
+		"check.ssl_expiration",
+		"other check.ssl_renewed",
+		"other check.performance_drop",
+		"check.up",
+		"other check.something_new",
+	}, handled)
+}
+
+func TestWebhookHandler_Errors(t *testing.T) {
+	calls := 0
+	h := &WebhookHandler{
+		OnCheckDown: func(context.Context, Event) error {
+			calls++
+			return errors.New("boom")
+		},
+		MaxBodySize: 64,
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/updown", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(`not json`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(webhookBatch)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// The error of the callback is logged, not returned
+	var logged bytes.Buffer
+	h.ErrorLog = log.New(&logged, "", 0)
+	h.MaxBodySize = 0
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(webhookBatch)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "handling the events failed\n", w.Body.String())
+	assert.Equal(t, "[ERROR] webhook: handling event 0 (check.down of check abcd): boom\n", logged.String())
+	assert.Equal(t, 1, calls)
+}
+
+func TestWebhookHandler_OnEvents(t *testing.T) {
+	var batches [][]Event
+	h := &WebhookHandler{
+		OnCheckDown: func(context.Context, Event) error {
+			t.Error("the callback of the event type is called")
+			return nil
+		},
+		OnEvents: func(_ context.Context, events []Event) error {
+			batches = append(batches, events)
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(webhookBatch)))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 6)
+}
+
+func TestWebhookHandler_Secret(t *testing.T) {
+	calls := 0
+	h := &WebhookHandler{
+		OnEvent: func(context.Context, Event) error {
+			calls++
+			return nil
+		},
+		Secret: "s3cr3t",
+	}
+
+	for target, status := range map[string]int{
+		"/updown":              http.StatusUnauthorized,
+		"/updown?token=wrong":  http.StatusUnauthorized,
+		"/updown?token=s3cr3t": http.StatusNoContent,
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, strings.NewReader(`[]`)))
+		assert.Equal(t, status, w.Code, target)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/updown", strings.NewReader(webhookBatch))
+	req.Header.Set(WebhookSecretHeader, "s3cr3t")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 6, calls)
+}